@@ -5,7 +5,6 @@ import (
 )
 
 func TestREADME_Ex1(t *testing.T) {
-	EnableDebug = true
 	const (
 		// 'root' values
 		Px PIdx_F32 = PIdx_F32(iota)
@@ -31,8 +30,6 @@ func TestREADME_Ex1(t *testing.T) {
 	const _end uint16 = uint16(_F32_PARAMS_END)
 
 	var MyParamTable = func() ParamTable {
-		// This is the default library setting, used for identifying table issues during development
-		EnableDebug = true
 		// each parameter denotes the END index for the data type, see the full template for details
 		table := NewParamTable(
 			PIdx_U64(0),
@@ -47,7 +44,9 @@ func TestREADME_Ex1(t *testing.T) {
 			PIdx_U8(_end),
 			PIdx_I8(_end),
 			PIdx_Bool(_end),
-			_CALC_COUNT)
+			_CALC_COUNT,
+			// This is the default library setting, used for identifying table issues during development
+			WithDebug(true))
 		// First, register your calculation/update functions
 		table.RegisterCalc(CalcVal_Plus_32_0, func(c *CalcInterface) {
 			inVal := c.GetInput_F32(0) // get the first function parameter as a float32
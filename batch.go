@@ -0,0 +1,174 @@
+package go_param_table
+
+import (
+	"unsafe"
+)
+
+// The value a root held before it was first written inside a batch level
+type batchEntry struct {
+	idx     uint32
+	typeIdx uint8
+	old     savedValue
+}
+
+// A parameter value copied out of the table. Pointer, string and opaque values are kept where
+// the garbage collector can see them instead of in raw
+type savedValue struct {
+	raw [8]byte
+	ptr unsafe.Pointer
+	str string
+	val any
+}
+
+type batchState struct {
+	log    []batchEntry
+	starts []int
+}
+
+func (b *batchState) memoryFootprint() uintptr {
+	size := uintptr(cap(b.log)) * unsafe.Sizeof(batchEntry{})
+	size += uintptr(cap(b.starts)) * unsafe.Sizeof(int(0))
+	return size
+}
+
+// Starts a batch: until the matching CommitBatch(), every SetRoot_*() only stores the new root
+// value and derived values are not updated. Batches may be nested, in which case only the
+// outermost CommitBatch() updates derived values.
+//
+// Reading values inside a batch returns the staged root values, but derived values
+// still reflect the state from before the batch began
+func (t *ParamTableOf[E]) BeginBatch() {
+	t.batch.starts = append(t.batch.starts, len(t.batch.log))
+}
+
+// Whether a batch started with BeginBatch() is still open
+func (t *ParamTableOf[E]) InBatch() bool {
+	return len(t.batch.starts) > 0
+}
+
+// Ends the innermost open batch. If it was the outermost batch, all root values changed
+// within it are propagated together, triggering every affected calculation exactly once
+func (t *ParamTableOf[E]) CommitBatch() {
+	b := &t.batch
+	t.checkBatchOpen("CommitBatch")
+	b.starts = b.starts[:len(b.starts)-1]
+	if len(b.starts) > 0 {
+		return
+	}
+	marks := t.prop.marks
+	// the first entry logged for each root holds its value from before the batch began
+	for i := range b.log {
+		e := &b.log[i]
+		if marks[e.idx]&_PROP_STAGED != 0 {
+			continue
+		}
+		marks[e.idx] |= _PROP_STAGED
+		changed := t.changedSinceStaged(e)
+		if changed && t.history != nil {
+			t.history.recordChange(e, t.saveValue(e.idx, int(e.typeIdx)))
+		}
+		if changed || getFlag(e.idx, t.flags).AlwaysUpdate() {
+			t.markChanged(E(e.idx))
+		}
+	}
+	for _, e := range b.log {
+		marks[e.idx] &^= _PROP_STAGED
+	}
+	if t.history != nil {
+		t.history.commitEntry()
+	}
+	// the spare capacity must not keep old pointer values alive
+	clear(b.log)
+	b.log = b.log[:0]
+	t.propagate()
+}
+
+// Ends the innermost open batch, restoring every root value set within it (including
+// within any batches nested inside it) to the value it had when that batch began
+func (t *ParamTableOf[E]) Rollback() {
+	b := &t.batch
+	t.checkBatchOpen("Rollback")
+	start := b.starts[len(b.starts)-1]
+	for i := len(b.log) - 1; i >= start; i -= 1 {
+		e := &b.log[i]
+		t.loadValue(e.idx, int(e.typeIdx), &e.old)
+	}
+	clear(b.log[start:])
+	b.log = b.log[:start]
+	b.starts = b.starts[:len(b.starts)-1]
+}
+
+func (t *ParamTableOf[E]) checkBatchOpen(funcName string) {
+	if len(t.batch.starts) == 0 {
+		t.fail(newParamError(ErrNoBatch, ^E(0), "%s(): no batch is open, call BeginBatch() first", funcName))
+	}
+}
+
+// Logs the current value of a root that is about to be set, if a batch is open
+func (t *ParamTableOf[E]) stageRoot(idx uint32, typeIdx int) {
+	if len(t.batch.starts) == 0 || idx >= uint32(len(t.hookups)) {
+		return
+	}
+	t.batch.log = append(t.batch.log, batchEntry{
+		idx:     idx,
+		typeIdx: uint8(typeIdx),
+		old:     t.saveValue(idx, typeIdx),
+	})
+}
+
+// Called after a root value changed: propagates immediately, or leaves it for CommitBatch()
+func (t *ParamTableOf[E]) rootChanged(idx E) {
+	if len(t.batch.starts) == 0 {
+		t.propagateFrom(idx)
+	}
+}
+
+// Whether the root of a batch entry holds a different value than it did when it was staged
+func (t *ParamTableOf[E]) changedSinceStaged(e *batchEntry) bool {
+	current := t.saveValue(e.idx, int(e.typeIdx))
+	return !t.savedEqual(e.idx, int(e.typeIdx), &e.old, &current)
+}
+
+// Copies the value of idx out of the table
+func (t *ParamTableOf[E]) saveValue(idx uint32, typeIdx int) (v savedValue) {
+	memPtr, subIdx := t.getBytePtr(idx, typeIdx)
+	switch typeIdx {
+	case typePtr:
+		v.ptr = t.ptrs[subIdx]
+	case typeStr:
+		v.str = t.strs[subIdx]
+	case typeVal:
+		v.val = t.vals[subIdx]
+	default:
+		copy(v.raw[:], unsafe.Slice(memPtr, sizeTable[typeIdx]))
+	}
+	return
+}
+
+// Writes a value copied by saveValue() back into idx, without propagating it
+func (t *ParamTableOf[E]) loadValue(idx uint32, typeIdx int, v *savedValue) {
+	memPtr, subIdx := t.getBytePtr(idx, typeIdx)
+	switch typeIdx {
+	case typePtr:
+		t.ptrs[subIdx] = v.ptr
+	case typeStr:
+		t.strs[subIdx] = v.str
+	case typeVal:
+		t.vals[subIdx] = v.val
+	default:
+		copy(unsafe.Slice(memPtr, sizeTable[typeIdx]), v.raw[:])
+	}
+}
+
+// Whether two values copied out of idx are equal, by the equality func of idx for opaque values
+func (t *ParamTableOf[E]) savedEqual(idx uint32, typeIdx int, a *savedValue, b *savedValue) bool {
+	switch typeIdx {
+	case typePtr:
+		return a.ptr == b.ptr
+	case typeStr:
+		return a.str == b.str
+	case typeVal:
+		return t.valsEqual(idx-t.idxOffsets[typeVal], a.val, b.val)
+	}
+	return a.raw == b.raw
+}
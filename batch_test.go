@@ -0,0 +1,114 @@
+package go_param_table
+
+import (
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	t.Run("narrow", testBatch[uint16])
+	t.Run("wide", testBatch[uint32])
+}
+
+func testBatch[E Index](t *testing.T) {
+	const (
+		Pw PIdx_F32 = PIdx_F32(iota)
+		Ph
+		Area
+		_F32_PARAMS_END
+	)
+	const (
+		CalcArea PIdx_Calc = iota
+		_CALC_COUNT
+	)
+	type seen struct{ w, h float32 }
+	var observed []seen
+	table := newTestTableOf[E](ParamLayout{F32End: _F32_PARAMS_END}, _CALC_COUNT, WithDebug(true))
+	table.RegisterCalc(CalcArea, func(c *CalcInterfaceOf[E]) {
+		w, h := c.GetInput_F32(0), c.GetInput_F32(1)
+		observed = append(observed, seen{w, h})
+		c.SetOutput_F32(0, w*h)
+	})
+	table.InitRoot_F32(Pw, 10, false)
+	table.InitRoot_F32(Ph, 20, false)
+	table.InitDerived_F32(Area, false, CalcArea, []E{E(Pw), E(Ph)}, []E{E(Area)})
+
+	var expect = func(idx PIdx_F32, val float32) {
+		t.Helper()
+		if got := table.Get_F32(idx); got != val {
+			t.Errorf("value error at idx %d:\n\tEXP: %f\n\tGOT: %f", idx, val, got)
+		}
+	}
+	var expectObserved = func(exp ...seen) {
+		t.Helper()
+		if len(observed) != len(exp) {
+			t.Fatalf("calc triggered %d times, expected %d (%v)", len(observed), len(exp), observed)
+		}
+		for i := range exp {
+			if observed[i] != exp[i] {
+				t.Errorf("calc observed inputs %v, expected %v", observed[i], exp[i])
+			}
+		}
+		observed = observed[:0]
+	}
+	expectObserved(seen{10, 20})
+
+	// single commit, calc sees both new values
+	table.BeginBatch()
+	table.SetRoot_F32(Pw, 30)
+	table.SetRoot_F32(Ph, 40)
+	expect(Pw, 30)
+	expect(Area, 200)
+	expectObserved()
+	table.CommitBatch()
+	expect(Area, 1200)
+	expectObserved(seen{30, 40})
+
+	// values set back to what they were before the batch propagate nothing
+	table.BeginBatch()
+	table.SetRoot_F32(Pw, 1)
+	table.SetRoot_F32(Pw, 30)
+	table.CommitBatch()
+	expectObserved()
+
+	// nested commit merges into the outer batch, nested rollback only undoes the inner batch
+	table.BeginBatch()
+	table.SetRoot_F32(Pw, 5)
+	table.BeginBatch()
+	table.SetRoot_F32(Ph, 6)
+	table.CommitBatch()
+	table.BeginBatch()
+	table.SetRoot_F32(Pw, 7)
+	table.SetRoot_F32(Ph, 8)
+	table.Rollback()
+	expect(Pw, 5)
+	expect(Ph, 6)
+	expectObserved()
+	if !table.InBatch() {
+		t.Errorf("outer batch was closed by a nested commit/rollback")
+	}
+	table.CommitBatch()
+	expect(Area, 30)
+	expectObserved(seen{5, 6})
+
+	// full rollback restores pre-batch roots and leaves derived values untouched
+	table.BeginBatch()
+	table.SetRoot_F32(Pw, 100)
+	table.SetRoot_F32(Ph, 100)
+	table.Rollback()
+	expect(Pw, 5)
+	expect(Ph, 6)
+	expect(Area, 30)
+	expectObserved()
+	if table.InBatch() {
+		t.Errorf("batch still open after outermost rollback")
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("CommitBatch() without BeginBatch() did not cause panic")
+			}
+		}()
+		table.CommitBatch()
+	}()
+}
@@ -0,0 +1,420 @@
+package go_param_table
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"unsafe"
+)
+
+// The binary format written by MarshalBinary() and AppendBinary(). All numbers are little-endian:
+//
+//	magic        [4]byte "PTBL"
+//	version      uint16
+//	flags        uint16  (_BIN_FLAG_DERIVED if derived values are included)
+//	bodyLen      uint32  number of bytes after this field, checksum included
+//	paramCount   uint32
+//	regionCount  uint16  number of type regions (typeCount)
+//	idxOffsets   [regionCount]uint32
+//	graphSum     uint32  CRC-32 of the dependency graph, see graphChecksum()
+//	rootCount    uint32
+//	derivedCount uint32
+//	records      rootCount root records, then derivedCount derived records, each the uint32
+//	             index followed by the value: 1, 2, 4 or 8 bytes for numbers and bools, a uint32
+//	             length and the bytes for strings
+//	checksum     uint32  CRC-32 (IEEE) of every byte before it
+const (
+	_BIN_MAGIC               = "PTBL"
+	_BIN_VERSION      uint16 = 1
+	_BIN_FLAG_DERIVED        = 1 << 0
+	_BIN_PREFIX_LEN          = 4 + 2 + 2 + 4
+)
+
+var errInvalidBool = errors.New("bool value is neither 0 nor 1")
+
+// An option passed to AppendBinary() or WriteBinary()
+type BinaryOption func(b *binaryConfig)
+
+type binaryConfig struct {
+	derived bool
+}
+
+// Also writes every derived value. When the data is loaded into a table with the same dependency
+// graph they are written as-is instead of being recalculated, which makes cold starts of tables
+// with expensive calcs faster. With a different graph they are ignored
+func WithDerivedValues() BinaryOption {
+	return func(b *binaryConfig) {
+		b.derived = true
+	}
+}
+
+// Returns every initialized root value in the binary format, see AppendBinary()
+func (t *ParamTableOf[E]) MarshalBinary() ([]byte, error) {
+	return t.AppendBinary(nil)
+}
+
+// Appends the table layout and every initialized root value to buf in a versioned, checksummed
+// binary format that UnmarshalBinary() reads back. Pointer and opaque values are not written,
+// they only exist at runtime
+func (t *ParamTableOf[E]) AppendBinary(buf []byte, opts ...BinaryOption) ([]byte, error) {
+	var config binaryConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	start := len(buf)
+	flags := uint16(0)
+	if config.derived {
+		flags |= _BIN_FLAG_DERIVED
+	}
+	buf = append(buf, _BIN_MAGIC...)
+	buf = binary.LittleEndian.AppendUint16(buf, _BIN_VERSION)
+	buf = binary.LittleEndian.AppendUint16(buf, flags)
+	lenPos := len(buf)
+	buf = binary.LittleEndian.AppendUint32(buf, 0)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(t.hookups)))
+	buf = binary.LittleEndian.AppendUint16(buf, typeCount)
+	for _, offset := range t.idxOffsets {
+		buf = binary.LittleEndian.AppendUint32(buf, offset)
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, t.graphChecksum())
+	countPos := len(buf)
+	buf = binary.LittleEndian.AppendUint64(buf, 0)
+	var counts [2]uint32
+	passes := 1
+	if config.derived {
+		passes = 2
+	}
+	for pass := 0; pass < passes; pass += 1 {
+		derived := pass == 1
+		for idx := range t.hookups {
+			typeIdx := t.typeOfIdx(uint32(idx))
+			if !getFlag(uint32(idx), t.flags).IsInit() || t.isDerived(E(idx)) != derived || !serializable(typeIdx) {
+				continue
+			}
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(idx))
+			buf = t.appendValue(buf, uint32(idx), typeIdx)
+			counts[pass] += 1
+		}
+	}
+	binary.LittleEndian.PutUint32(buf[countPos:], counts[0])
+	binary.LittleEndian.PutUint32(buf[countPos+4:], counts[1])
+	binary.LittleEndian.PutUint32(buf[lenPos:], uint32(len(buf)-start-_BIN_PREFIX_LEN+4))
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf[start:])), nil
+}
+
+// Writes every initialized root value to w in the binary format, see AppendBinary()
+func (t *ParamTableOf[E]) WriteTo(w io.Writer) (int64, error) {
+	return t.WriteBinary(w)
+}
+
+// Same as WriteTo(), with the options of AppendBinary()
+func (t *ParamTableOf[E]) WriteBinary(w io.Writer, opts ...BinaryOption) (int64, error) {
+	buf, err := t.AppendBinary(nil, opts...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// Reads data written by MarshalBinary() or AppendBinary() by a table with the same layout.
+// Every root value in it is written (initializing roots that were not yet initialized), then
+// every value derived from a changed root is recalculated. If the data includes derived values
+// and the dependency graph of both tables is the same, they are written as-is instead, and only
+// the values that were not written are recalculated: derived pointer and opaque values (from
+// the roots they depend on), and values derived from pointer and opaque roots. Loading is not
+// recorded by the undo history.
+//
+// The error is an ErrFormat error if the data is truncated, corrupt or of an unknown version,
+// an ErrLayout error if it was written by a table with different type regions, an
+// ErrDerivedNotSettable error if it holds a root that is a derived value in this table, or an
+// ErrBatchOpen error if a batch is open. The table is not modified if an error is returned
+func (t *ParamTableOf[E]) UnmarshalBinary(data []byte) error {
+	null := ^E(0)
+	if len(t.batch.starts) > 0 {
+		return newParamError(ErrBatchOpen, null, "UnmarshalBinary(): a batch is open, call CommitBatch() or Rollback() first")
+	}
+	d := binaryDecoder{data: data}
+	if string(d.bytes(4)) != _BIN_MAGIC {
+		return newParamError(ErrFormat, null, "UnmarshalBinary(): data does not start with %q", _BIN_MAGIC)
+	}
+	if version := d.uint16(); version != _BIN_VERSION {
+		return newParamError(ErrFormat, null, "UnmarshalBinary(): unsupported format version %d (supported: %d)", version, _BIN_VERSION)
+	}
+	flags := d.uint16()
+	bodyLen := d.uint32()
+	if d.err != nil || uint64(len(data)) != _BIN_PREFIX_LEN+uint64(bodyLen) || bodyLen < 4 {
+		return newParamError(ErrFormat, null, "UnmarshalBinary(): data is %d bytes, expected %d", len(data), _BIN_PREFIX_LEN+uint64(bodyLen))
+	}
+	sumPos := len(data) - 4
+	if sum := binary.LittleEndian.Uint32(data[sumPos:]); sum != crc32.ChecksumIEEE(data[:sumPos]) {
+		return newParamError(ErrFormat, null, "UnmarshalBinary(): checksum mismatch, data is corrupt")
+	}
+	d.data = d.data[:len(d.data)-4]
+	paramCount := d.uint32()
+	var offsets [typeCount]uint32
+	regionCount := d.uint16()
+	if d.err == nil && regionCount != typeCount {
+		return newParamError(ErrLayout, null, "UnmarshalBinary(): data has %d type regions, this table has %d", regionCount, typeCount)
+	}
+	for i := range offsets {
+		offsets[i] = d.uint32()
+	}
+	graphSum := d.uint32()
+	rootCount := d.uint32()
+	derivedCount := d.uint32()
+	if d.err != nil {
+		return newParamError(ErrFormat, null, "UnmarshalBinary(): %s", d.err)
+	}
+	if paramCount != uint32(len(t.hookups)) || offsets != t.idxOffsets {
+		return newParamError(ErrLayout, null, "UnmarshalBinary(): data was written by a table with a different layout (%d parameters, region offsets %v; this table has %d, %v)", paramCount, offsets, len(t.hookups), t.idxOffsets)
+	}
+	records := make([]loadRecord, 0, min(uint64(rootCount)+uint64(derivedCount), uint64(len(d.data))/5))
+	for i := uint64(0); i < uint64(rootCount)+uint64(derivedCount); i += 1 {
+		idx := d.uint32()
+		if d.err != nil {
+			break
+		}
+		if idx >= paramCount {
+			return newParamError(ErrFormat, idx, "UnmarshalBinary(): record index %d is outside bounds of parameter list (len %d)", idx, paramCount)
+		}
+		typeIdx := t.typeOfIdx(idx)
+		if !serializable(typeIdx) {
+			return newParamError(ErrFormat, idx, "UnmarshalBinary(): record of index %s holds a %s value, which is never written", t.paramLabel(idx), typeNames[typeIdx])
+		}
+		derived := i >= uint64(rootCount)
+		if !derived && t.isDerived(E(idx)) {
+			return newParamError(ErrDerivedNotSettable, idx, "UnmarshalBinary(): index %s is a root value in the data, but a derived value in this table", t.paramLabel(idx))
+		}
+		records = append(records, loadRecord{idx: idx, derived: derived, val: d.value(typeIdx)})
+	}
+	if d.err == nil && len(d.data) > 0 {
+		d.err = io.ErrUnexpectedEOF
+	}
+	if d.err != nil {
+		return newParamError(ErrFormat, null, "UnmarshalBinary(): %s", d.err)
+	}
+
+	t.loadRecords(records, flags&_BIN_FLAG_DERIVED != 0 && graphSum == t.graphChecksum())
+	return nil
+}
+
+// A value read by UnmarshalBinary() or UnmarshalJSON(), written into idx by loadRecords()
+type loadRecord struct {
+	idx     uint32
+	derived bool
+	val     savedValue
+}
+
+// Writes the records and recalculates what depends on them as one update, which the undo
+// history does not record. Without withDerived the derived records are ignored and the roots
+// are written in a batch. With it the derived records are written as-is, and only the values
+// that could not be loaded (pointer and opaque values, and everything derived from them) are
+// recalculated
+func (t *ParamTableOf[E]) loadRecords(records []loadRecord, withDerived bool) {
+	if t.history != nil {
+		t.history.applying = true
+		defer func() {
+			t.history.applying = false
+			t.history.sealed = true
+		}()
+	}
+	if !withDerived {
+		t.BeginBatch()
+		t.writeRecords(records, false)
+		t.CommitBatch()
+		return
+	}
+	t.writeRecords(records, true)
+	for idx := range t.hookups {
+		typeIdx := t.typeOfIdx(uint32(idx))
+		if !serializable(typeIdx) && getFlag(uint32(idx), t.flags).IsInit() && !t.isDerived(E(idx)) {
+			t.markChanged(E(idx))
+		}
+	}
+	t.markRuntimeAncestors()
+	t.propagate()
+	t.notifyAll()
+}
+
+func (t *ParamTableOf[E]) writeRecords(records []loadRecord, withDerived bool) {
+	for i := range records {
+		r := &records[i]
+		if r.derived && (!withDerived || !t.isDerived(E(r.idx))) {
+			continue
+		}
+		typeIdx := t.typeOfIdx(r.idx)
+		t.stageRoot(r.idx, typeIdx)
+		if !r.derived {
+			setFlag(r.idx, t.flags, _PFLAG_INIT)
+		}
+		t.loadValue(r.idx, typeIdx, &r.val)
+	}
+}
+
+// Marks as changed every initialized root that a derived pointer or opaque value depends on,
+// directly or not: those values are never written, so they must be recalculated from their roots
+func (t *ParamTableOf[E]) markRuntimeAncestors() {
+	var stack []E
+	for idx := range t.hookups {
+		if t.isDerived(E(idx)) && !serializable(t.typeOfIdx(uint32(idx))) {
+			stack = append(stack, E(idx))
+		}
+	}
+	if len(stack) == 0 {
+		return
+	}
+	visited := make([]bool, len(t.hookups))
+	for len(stack) > 0 {
+		idx := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[idx] {
+			continue
+		}
+		visited[idx] = true
+		if !t.isDerived(idx) {
+			if getFlag(uint32(idx), t.flags).IsInit() {
+				t.markChanged(idx)
+			}
+			continue
+		}
+		stack = append(stack, t.getParents(idx)...)
+	}
+}
+
+// Reads data written by WriteTo() or WriteBinary() from r, see UnmarshalBinary(). Exactly the
+// bytes of the data are read, so more data may follow it in r
+func (t *ParamTableOf[E]) ReadFrom(r io.Reader) (int64, error) {
+	prefix := make([]byte, _BIN_PREFIX_LEN)
+	n, err := io.ReadFull(r, prefix)
+	if err != nil {
+		return int64(n), newParamError(ErrFormat, ^E(0), "ReadFrom(): %s", err)
+	}
+	if !bytes.Equal(prefix[:4], []byte(_BIN_MAGIC)) {
+		return int64(n), newParamError(ErrFormat, ^E(0), "ReadFrom(): data does not start with %q", _BIN_MAGIC)
+	}
+	// the length is not trusted: the buffer only grows as the body is actually read
+	bodyLen := int64(binary.LittleEndian.Uint32(prefix[8:]))
+	var data bytes.Buffer
+	data.Write(prefix)
+	m, err := data.ReadFrom(io.LimitReader(r, bodyLen))
+	if err == nil && m < bodyLen {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return int64(n) + m, newParamError(ErrFormat, ^E(0), "ReadFrom(): %s", err)
+	}
+	return int64(n) + m, t.UnmarshalBinary(data.Bytes())
+}
+
+// Pointer and opaque values only exist at runtime, every other type is written
+func serializable(typeIdx int) bool {
+	return typeIdx != typePtr && typeIdx != typeVal
+}
+
+func (t *ParamTableOf[E]) appendValue(buf []byte, idx uint32, typeIdx int) []byte {
+	memPtr, subIdx := t.getBytePtr(idx, typeIdx)
+	ptr := unsafe.Pointer(memPtr)
+	if typeIdx == typeStr {
+		str := t.strs[subIdx]
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(str)))
+		return append(buf, str...)
+	}
+	switch sizeTable[typeIdx] {
+	case size64:
+		return binary.LittleEndian.AppendUint64(buf, *(*uint64)(ptr))
+	case size32:
+		return binary.LittleEndian.AppendUint32(buf, *(*uint32)(ptr))
+	case size16:
+		return binary.LittleEndian.AppendUint16(buf, *(*uint16)(ptr))
+	default:
+		return append(buf, *memPtr)
+	}
+}
+
+// Reads little-endian numbers from data, setting err instead of reading past its end
+type binaryDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *binaryDecoder) bytes(n uint64) []byte {
+	if d.err != nil || uint64(len(d.data)) < n {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *binaryDecoder) uint16() uint16 {
+	if b := d.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (d *binaryDecoder) uint32() uint32 {
+	if b := d.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *binaryDecoder) uint64() uint64 {
+	if b := d.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// Reads a value of the type into the layout saveValue() uses
+func (d *binaryDecoder) value(typeIdx int) (v savedValue) {
+	if typeIdx == typeStr {
+		v.str = string(d.bytes(uint64(d.uint32())))
+		return
+	}
+	ptr := unsafe.Pointer(&v.raw)
+	switch sizeTable[typeIdx] {
+	case size64:
+		*(*uint64)(ptr) = d.uint64()
+	case size32:
+		*(*uint32)(ptr) = d.uint32()
+	case size16:
+		*(*uint16)(ptr) = d.uint16()
+	default:
+		if b := d.bytes(1); b != nil {
+			v.raw[0] = b[0]
+		}
+		if typeIdx == typeBool && v.raw[0] > 1 && d.err == nil {
+			d.err = errInvalidBool
+		}
+	}
+	return
+}
+
+// A CRC-32 of the calc, inputs and outputs of every derived value, which two tables share when
+// their calcs are hooked up the same way
+func (t *ParamTableOf[E]) graphChecksum() uint32 {
+	var sum uint32
+	var buf []byte
+	for idx := range t.hookups {
+		if !t.isDerived(E(idx)) {
+			continue
+		}
+		buf = buf[:0]
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(idx))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(t.hookupData[uint32(t.hookups[idx])+_HOOK_OFF_CALC]))
+		for _, list := range [2][]E{t.getParents(E(idx)), t.getSiblings(E(idx))} {
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(list)))
+			for _, i := range list {
+				buf = binary.LittleEndian.AppendUint32(buf, uint32(i))
+			}
+		}
+		sum = crc32.Update(sum, crc32.IEEETable, buf)
+	}
+	return sum
+}
@@ -0,0 +1,249 @@
+package go_param_table
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+type binaryTestTable[E Index] struct {
+	table   ParamTableOf[E]
+	evals   *int
+	width   PIdx_F64
+	height  PIdx_F64
+	area    PIdx_F64
+	label   PIdx_Str
+	count   PIdx_U8
+	enabled PIdx_Bool
+	offset  PIdx_I16
+	data    PIdx_Ptr
+	scaled  PIdx_F64
+}
+
+// width * height -> area -> label, and data -> scaled
+func newBinaryTestTable[E Index]() binaryTestTable[E] {
+	b := newTestBuilderOf[E](WithDebug(true), WithVerbosity(VerbositySilent))
+	width, height, area, scaled := b.F64("width"), b.F64("height"), b.F64("area"), b.F64("scaled")
+	label, count, enabled, offset, data := b.Str("label"), b.U8("count"), b.Bool("enabled"), b.I16("offset"), b.Ptr("data")
+	evals := new(int)
+	calcArea := b.Calc("area", func(c *CalcInterfaceOf[E]) {
+		*evals += 1
+		c.SetOutput_F64(0, c.GetInput_F64(0)*c.GetInput_F64(1))
+	})
+	calcLabel := b.Calc("label", func(c *CalcInterfaceOf[E]) {
+		*evals += 1
+		c.SetOutput_Str(0, fmt.Sprintf("%.0f px²", c.GetInput_F64(0)))
+	})
+	calcScaled := b.Calc("scaled", func(c *CalcInterfaceOf[E]) {
+		*evals += 1
+		c.SetOutput_F64(0, *(*float64)(c.GetInput_Ptr(0))*10)
+	})
+	g := binaryTestTable[E]{table: b.Build(), evals: evals}
+	g.width, g.height, g.area, g.label, g.scaled = *width, *height, *area, *label, *scaled
+	g.count, g.enabled, g.offset, g.data = *count, *enabled, *offset, *data
+	g.table.InitRoot_F64(g.width, 2, false)
+	g.table.InitRoot_F64(g.height, 3, false)
+	g.table.InitRoot_U8(g.count, 1, false)
+	g.table.InitRoot_I16(g.offset, -1, false)
+	g.table.InitRoot_Ptr(g.data, unsafe.Pointer(new(float64)), false)
+	g.table.InitDerived_F64(g.area, false, *calcArea, []E{E(g.width), E(g.height)}, []E{E(g.area)})
+	g.table.InitDerived_Str(g.label, false, *calcLabel, []E{E(g.area)}, []E{E(g.label)})
+	g.table.InitDerived_F64(g.scaled, false, *calcScaled, []E{E(g.data)}, []E{E(g.scaled)})
+	return g
+}
+
+func (g *binaryTestTable[E]) expect(t *testing.T, name string, width, height float64, label string, count uint8, enabled bool, offset int16) {
+	t.Helper()
+	tb := &g.table
+	if tb.Get_F64(g.width) != width || tb.Get_F64(g.height) != height || tb.Get_F64(g.area) != width*height {
+		t.Errorf("%s: numbers:\n\tEXP: %f * %f = %f\n\tGOT: %f * %f = %f", name, width, height, width*height, tb.Get_F64(g.width), tb.Get_F64(g.height), tb.Get_F64(g.area))
+	}
+	if tb.Get_Str(g.label) != label || tb.Get_U8(g.count) != count || tb.IsInit(E(g.enabled)) != enabled || tb.Get_I16(g.offset) != offset {
+		t.Errorf("%s: values:\n\tEXP: %q %d %t %d\n\tGOT: %q %d %t %d", name, label, count, enabled, offset, tb.Get_Str(g.label), tb.Get_U8(g.count), tb.IsInit(E(g.enabled)), tb.Get_I16(g.offset))
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	t.Run("narrow", testBinaryRoundTrip[uint16])
+	t.Run("wide", testBinaryRoundTrip[uint32])
+}
+
+func testBinaryRoundTrip[E Index](t *testing.T) {
+	src := newBinaryTestTable[E]()
+	src.table.SetRoot_F64(src.width, 4)
+	src.table.SetRoot_U8(src.count, 200)
+	src.table.SetRoot_I16(src.offset, -300)
+	src.table.InitRoot_Bool(src.enabled, true, false)
+	data, err := src.table.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("PTBL\x01\x00\x00\x00")) {
+		t.Errorf("header:\n\tEXP: PTBL, version 1, no flags\n\tGOT: % x", data[:8])
+	}
+
+	dst := newBinaryTestTable[E]()
+	*dst.evals = 0
+	if err := dst.table.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	dst.expect(t, "roots", 4, 3, "12 px²", 200, true, -300)
+	if *dst.evals != 2 {
+		t.Errorf("roots: %d calcs evaluated for 2 changed derived values", *dst.evals)
+	}
+
+	// derived values are written as-is, only values derived from pointers are recalculated
+	withDerived, _ := src.table.AppendBinary(nil, WithDerivedValues())
+	fast := newBinaryTestTable[E]()
+	*fast.evals = 0
+	if err := fast.table.UnmarshalBinary(withDerived); err != nil {
+		t.Fatalf("unmarshal with derived values: %v", err)
+	}
+	fast.expect(t, "derived", 4, 3, "12 px²", 200, true, -300)
+	if *fast.evals != 1 {
+		t.Errorf("derived: %d calcs evaluated, only the value derived from a pointer should be", *fast.evals)
+	}
+
+	// with another graph the derived values are recalculated instead
+	rewired := newBinaryTestTable[E]()
+	rewired.table.Rewire(E(rewired.label), 1, []E{E(rewired.width)}, []E{E(rewired.label)})
+	if err := rewired.table.UnmarshalBinary(withDerived); err != nil {
+		t.Fatalf("unmarshal with another graph: %v", err)
+	}
+	if got := rewired.table.Get_Str(rewired.label); got != "4 px²" {
+		t.Errorf("another graph:\n\tEXP: %q\n\tGOT: %q", "4 px²", got)
+	}
+
+	var buf bytes.Buffer
+	if _, err := src.table.WriteTo(&buf); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf.WriteString("trailing")
+	stream := newBinaryTestTable[E]()
+	if n, err := stream.table.ReadFrom(&buf); err != nil || n != int64(len(data)) {
+		t.Fatalf("read: %d bytes, %v", n, err)
+	}
+	stream.expect(t, "stream", 4, 3, "12 px²", 200, true, -300)
+	if buf.String() != "trailing" {
+		t.Errorf("read past the end of the data: %q left", buf.String())
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	t.Run("narrow", testBinaryErrors[uint16])
+	t.Run("wide", testBinaryErrors[uint32])
+}
+
+func testBinaryErrors[E Index](t *testing.T) {
+	src := newBinaryTestTable[E]()
+	src.table.SetRoot_F64(src.width, 10)
+	data, _ := src.table.MarshalBinary()
+	var expectErr = func(name string, data []byte, kind error, dst *ParamTableOf[E]) {
+		t.Helper()
+		if dst == nil {
+			fresh := newBinaryTestTable[E]()
+			dst = &fresh.table
+		}
+		before := dst.Snapshot()
+		if err := dst.UnmarshalBinary(data); !errors.Is(err, kind) {
+			t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", name, kind, err)
+		}
+		if !bytes.Equal(dst.values, before.values) || !slices.Equal(dst.strs, before.strs) || !slices.Equal(dst.flags, before.flags) {
+			t.Errorf("%s: failed load modified the table", name)
+		}
+	}
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)-10] ^= 0xff
+	expectErr("corrupt", corrupt, ErrFormat, nil)
+	expectErr("truncated", data[:len(data)-1], ErrFormat, nil)
+	expectErr("empty", nil, ErrFormat, nil)
+	future := bytes.Clone(data)
+	binary.LittleEndian.PutUint16(future[4:], 2)
+	expectErr("future version", future, ErrFormat, nil)
+
+	// a huge length in the header with a short body is not allocated up front
+	huge := bytes.Clone(data[:_BIN_PREFIX_LEN+8])
+	binary.LittleEndian.PutUint32(huge[8:], math.MaxUint32)
+	hugeDst := newBinaryTestTable[E]()
+	if n, err := hugeDst.table.ReadFrom(bytes.NewReader(huge)); !errors.Is(err, ErrFormat) || n != int64(len(huge)) {
+		t.Errorf("huge length:\n\tEXP: %d bytes, %v\n\tGOT: %d bytes, %v", len(huge), ErrFormat, n, err)
+	}
+
+	other, _ := newF64TestTableOf[E](4)
+	other.InitRoot_F64(0, 1, false)
+	otherData, _ := other.MarshalBinary()
+	expectErr("other layout", otherData, ErrLayout, nil)
+
+	derived := newBinaryTestTable[E]()
+	derived.table.Rewire(E(derived.height), 0, []E{E(derived.width), E(derived.width)}, []E{E(derived.height)})
+	expectErr("root is derived", data, ErrDerivedNotSettable, &derived.table)
+
+	open := newBinaryTestTable[E]()
+	open.table.BeginBatch()
+	expectErr("open batch", data, ErrBatchOpen, &open.table)
+}
+
+func TestBinaryOpaqueDerived(t *testing.T) {
+	t.Run("narrow", testBinaryOpaqueDerived[uint16])
+	t.Run("wide", testBinaryOpaqueDerived[uint32])
+}
+
+func testBinaryOpaqueDerived[E Index](t *testing.T) {
+	// base -> list (opaque) -> first
+	newTable := func(base float64) (ParamTableOf[E], PIdx_F64, PIdx_Val[[]float64], PIdx_F64) {
+		b := newTestBuilderOf[E](WithDebug(true), WithVerbosity(VerbositySilent))
+		basePtr, firstPtr, listPtr := b.F64("base"), b.F64("first"), DeclareVal[[]float64](b, "list")
+		calcList := b.Calc("list", func(c *CalcInterfaceOf[E]) {
+			OutputVal(c, 0, []float64{c.GetInput_F64(0) * 9})
+		})
+		calcFirst := b.Calc("first", func(c *CalcInterfaceOf[E]) {
+			c.SetOutput_F64(0, InputVal[[]float64](c, 0)[0])
+		})
+		table := b.Build()
+		base_, first, list := *basePtr, *firstPtr, *listPtr
+		table.InitRoot_F64(base_, base, false)
+		InitDerivedVal(&table, list, false, slices.Equal[[]float64], *calcList, []E{E(base_)}, []E{E(list)})
+		table.InitDerived_F64(first, false, *calcFirst, []E{E(list)}, []E{E(first)})
+		return table, base_, list, first
+	}
+	src, _, _, _ := newTable(1)
+	data, _ := src.AppendBinary(nil, WithDerivedValues())
+	dst, base, list, first := newTable(3)
+	if err := dst.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := GetVal(&dst, list); dst.Get_F64(base) != 1 || !slices.Equal(got, []float64{9}) || dst.Get_F64(first) != 9 {
+		t.Errorf("opaque derived value not recalculated:\n\tEXP: 1 -> [9] -> 9\n\tGOT: %f -> %v -> %f", dst.Get_F64(base), got, dst.Get_F64(first))
+	}
+}
+
+func TestBinaryHistory(t *testing.T) {
+	t.Run("narrow", testBinaryHistory[uint16])
+	t.Run("wide", testBinaryHistory[uint32])
+}
+
+func testBinaryHistory[E Index](t *testing.T) {
+	src := newBinaryTestTable[E]()
+	src.table.SetRoot_F64(src.width, 4)
+	for _, opts := range [][]BinaryOption{nil, {WithDerivedValues()}} {
+		data, _ := src.table.AppendBinary(nil, opts...)
+		dst := newBinaryTestTable[E]()
+		dst.table.EnableHistory()
+		dst.table.SetRoot_F64(dst.height, 5)
+		if err := dst.table.UnmarshalBinary(data); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(dst.table.history.entries) != 1 {
+			t.Errorf("load recorded by the undo history: %d entries", len(dst.table.history.entries))
+		}
+		dst.expect(t, "loaded", 4, 3, "12 px²", 1, false, -1)
+		dst.table.SetRoot_U8(dst.count, 2)
+		dst.table.Undo()
+		dst.expect(t, "undo after load", 4, 3, "12 px²", 1, false, -1)
+	}
+}
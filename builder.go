@@ -0,0 +1,183 @@
+package go_param_table
+
+import "unsafe"
+
+// Declares the parameters and calcs of a table one at a time, in any order, and lays them out
+// into the type regions NewParamTable() requires when the table is built. This makes the manual
+// constant template optional:
+//
+//	b := NewTableBuilder()
+//	width := b.F32("rect.width")
+//	height := b.F32("rect.height")
+//	area := b.F32("rect.area")
+//	calcArea := b.Calc("rect.area", func(c *CalcInterface) { ... })
+//	table := b.Build()
+//	table.InitRoot_F32(*width, 10, false)
+//
+// The handles returned by the declaration functions hold the null index of the table
+// (PIDX_NULL or WIDE_PIDX_NULL) until Build() fills them in
+type TableBuilderOf[E Index] struct {
+	params [typeCount][]builderParam
+	calcs  []builderCalc[E]
+	opts   []TableOption
+	built  bool
+}
+
+type (
+	TableBuilder     = TableBuilderOf[uint16]
+	WideTableBuilder = TableBuilderOf[uint32]
+)
+
+type builderParam struct {
+	name   string
+	assign func(idx uint32)
+}
+
+type builderCalc[E Index] struct {
+	name   string
+	calc   ParamCalcOf[E]
+	opts   []CalcOption
+	handle *PIdx_Calc
+}
+
+// Creates an empty builder, the options are passed on to the table when it is built
+func NewTableBuilder(opts ...TableOption) *TableBuilder {
+	return &TableBuilder{opts: opts}
+}
+
+// Same as NewTableBuilder(), but builds a WideParamTable
+func NewWideTableBuilder(opts ...TableOption) *WideTableBuilder {
+	return &WideTableBuilder{opts: opts}
+}
+
+// but non-empty names must be unique within the table and must not be decimal numbers. Build()
+// registers them as the Name of each parameter's ParamMeta
+func Declare[T Scalar, E Index](b *TableBuilderOf[E], name string) *Param[T] {
+	p := new(Param[T])
+	*p = Param[T](^E(0))
+	typeIdx := typeIdxOf[T]()
+	b.params[typeIdx] = append(b.params[typeIdx], builderParam{
+		name:   name,
+		assign: func(idx uint32) { *p = Param[T](idx) },
+	})
+	return p
+}
+
+// Same as Declare(), but declares an opaque value parameter of any type T
+func DeclareVal[T any, E Index](b *TableBuilderOf[E], name string) *PIdx_Val[T] {
+	p := new(PIdx_Val[T])
+	*p = PIdx_Val[T](^E(0))
+	b.params[typeVal] = append(b.params[typeVal], builderParam{
+		name:   name,
+		assign: func(idx uint32) { *p = PIdx_Val[T](idx) },
+	})
+	return p
+}
+
+func (b *TableBuilderOf[E]) U64(name string) *PIdx_U64   { return Declare[uint64](b, name) }
+func (b *TableBuilderOf[E]) I64(name string) *PIdx_I64   { return Declare[int64](b, name) }
+func (b *TableBuilderOf[E]) F64(name string) *PIdx_F64   { return Declare[float64](b, name) }
+func (b *TableBuilderOf[E]) Ptr(name string) *PIdx_Ptr   { return Declare[unsafe.Pointer](b, name) }
+func (b *TableBuilderOf[E]) Str(name string) *PIdx_Str   { return Declare[string](b, name) }
+func (b *TableBuilderOf[E]) U32(name string) *PIdx_U32   { return Declare[uint32](b, name) }
+func (b *TableBuilderOf[E]) I32(name string) *PIdx_I32   { return Declare[int32](b, name) }
+func (b *TableBuilderOf[E]) F32(name string) *PIdx_F32   { return Declare[float32](b, name) }
+func (b *TableBuilderOf[E]) U16(name string) *PIdx_U16   { return Declare[uint16](b, name) }
+func (b *TableBuilderOf[E]) I16(name string) *PIdx_I16   { return Declare[int16](b, name) }
+func (b *TableBuilderOf[E]) U8(name string) *PIdx_U8     { return Declare[uint8](b, name) }
+func (b *TableBuilderOf[E]) I8(name string) *PIdx_I8     { return Declare[int8](b, name) }
+func (b *TableBuilderOf[E]) Bool(name string) *PIdx_Bool { return Declare[bool](b, name) }
+
+// Declares a calc and returns its handle, filled in by Build(), which also registers the calc
+// with opts
+func (b *TableBuilderOf[E]) Calc(name string, calc ParamCalcOf[E], opts ...CalcOption) *PIdx_Calc {
+	handle := new(PIdx_Calc)
+	*handle = PIdx_Calc(^E(0))
+	b.calcs = append(b.calcs, builderCalc[E]{name: name, calc: calc, opts: opts, handle: handle})
+	return handle
+}
+
+// Lays out all declared parameters, creates the table, registers all declared calcs and fills
+// in every handle. Panics if the declarations are invalid (see TryBuild())
+func (b *TableBuilderOf[E]) Build() ParamTableOf[E] {
+	table, err := b.TryBuild()
+	if err != nil {
+		table.fail(err)
+	}
+	return table
+}
+
+// Same as Build(), but returns an ErrLayout, ErrDuplicateName or ErrInvalidName error instead of panicking. No handle is modified
+// if an error is returned
+func (b *TableBuilderOf[E]) TryBuild() (ParamTableOf[E], error) {
+	if err := b.layoutErr(); err != nil {
+		table := ParamTableOf[E]{debug: defaultDebugConfig()}
+		table.Configure(b.opts...)
+		return table, err
+	}
+	var ends [typeCount]uint32
+	next := uint32(0)
+	for typeIdx := range b.params {
+		next += uint32(len(b.params[typeIdx]))
+		ends[typeIdx] = next
+	}
+	table := newParamTableFromEnds[E](ends, PIdx_Calc(len(b.calcs)), b.opts...)
+	for typeIdx, params := range b.params {
+		for i, p := range params {
+			idx := table.idxOffsets[typeIdx] + uint32(i)
+			p.assign(idx)
+			if p.name != "" {
+				table.SetMeta(E(idx), ParamMeta{Name: p.name})
+			}
+		}
+	}
+	for i, c := range b.calcs {
+		*c.handle = PIdx_Calc(i)
+		if c.calc != nil {
+			table.RegisterCalc(PIdx_Calc(i), c.calc, append([]CalcOption{WithCalcName(c.name)}, c.opts...)...)
+		}
+	}
+	b.built = true
+	return table, nil
+}
+
+func (b *TableBuilderOf[E]) layoutErr() error {
+	null := ^E(0)
+	if b.built {
+		return newParamError(ErrLayout, null, "Build(): builder was already built, its handles belong to the first table")
+	}
+	total := 0
+	seen := make(map[string]struct{})
+	for _, params := range b.params {
+		total += len(params)
+		for _, p := range params {
+			if p.name == "" {
+				continue
+			}
+			if isIndexName(p.name) {
+				return newParamError(ErrInvalidName, null, "Build(): parameter name %q is a number, which reads like an index", p.name)
+			}
+			if _, dup := seen[p.name]; dup {
+				return newParamError(ErrDuplicateName, null, "Build(): parameter name %q declared more than once", p.name)
+			}
+			seen[p.name] = struct{}{}
+		}
+	}
+	if total >= int(null) {
+		return newParamError(ErrLayout, null, "Build(): %d parameters declared (max = %d)", total, null-1)
+	}
+	if len(b.calcs) >= int(null) {
+		return newParamError(ErrLayout, null, "Build(): %d calcs declared (max = %d)", len(b.calcs), null-1)
+	}
+	clear(seen)
+	for _, c := range b.calcs {
+		if c.name == "" {
+			continue
+		}
+		if _, dup := seen[c.name]; dup {
+			return newParamError(ErrDuplicateName, null, "Build(): calc name %q declared more than once", c.name)
+		}
+		seen[c.name] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package go_param_table
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTableBuilder(t *testing.T) {
+	t.Run("narrow", testTableBuilder[uint16])
+	t.Run("wide", testTableBuilder[uint32])
+}
+
+func testTableBuilder[E Index](t *testing.T) {
+	b := newTestBuilderOf[E](WithDebug(true), WithVerbosity(VerbositySilent))
+	// declared out of type order on purpose
+	visible := b.Bool("rect.visible")
+	width := b.F32("rect.width")
+	count := b.U64("rect.count")
+	height := b.F32("rect.height")
+	area := b.F32("rect.area")
+	total := b.F64("rect.total")
+	calcArea := b.Calc("area", func(c *CalcInterfaceOf[E]) {
+		c.SetOutput_F32(0, c.GetInput_F32(0)*c.GetInput_F32(1))
+	})
+	calcTotal := b.Calc("total", func(c *CalcInterfaceOf[E]) {
+		c.SetOutput_F64(0, float64(c.GetInput_F32(0))*float64(c.GetInput_U64(1)))
+	})
+	if *width != Param[float32](^E(0)) || *calcArea != PIdx_Calc(^E(0)) {
+		t.Errorf("handles were assigned before Build()")
+	}
+	table := b.Build()
+
+	// regions must be laid out in the order NewParamTable() requires
+	for _, c := range []struct {
+		name string
+		exp  E
+		got  E
+	}{
+		{"count", 0, E(*count)},
+		{"total", 1, E(*total)},
+		{"width", 2, E(*width)},
+		{"height", 3, E(*height)},
+		{"area", 4, E(*area)},
+		{"visible", 5, E(*visible)},
+		{"calcArea", 0, E(*calcArea)},
+		{"calcTotal", 1, E(*calcTotal)},
+	} {
+		if c.exp != c.got {
+			t.Errorf("%s index:\n\tEXP: %d\n\tGOT: %d", c.name, c.exp, c.got)
+		}
+	}
+
+	table.InitRoot_Bool(*visible, true, false)
+	table.InitRoot_U64(*count, 3, false)
+	table.InitRoot_F32(*width, 2, false)
+	table.InitRoot_F32(*height, 5, false)
+	table.InitDerived_F32(*area, false, *calcArea, []E{E(*width), E(*height)}, []E{E(*area)})
+	InitDerived(&table, *total, false, *calcTotal, []AnyParam{*area, *count}, []AnyParam{*total})
+	table.SetRoot_F32(*height, 10)
+	if got := table.Get_F64(*total); got != 60 {
+		t.Errorf("value error:\n\tEXP: %f\n\tGOT: %f", 60.0, got)
+	}
+	if !table.Get_Bool(*visible) {
+		t.Errorf("value error:\n\tEXP: %t\n\tGOT: %t", true, false)
+	}
+
+	if _, err := b.TryBuild(); !errors.Is(err, ErrLayout) {
+		t.Errorf("second build:\n\tEXP: %v\n\tGOT: %v", ErrLayout, err)
+	}
+	dup := newTestBuilderOf[E]()
+	first := dup.U8("a")
+	dup.I8("a")
+	if _, err := dup.TryBuild(); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("duplicate name:\n\tEXP: %v\n\tGOT: %v", ErrDuplicateName, err)
+	}
+	if *first != Param[uint8](^E(0)) {
+		t.Errorf("failed build assigned handles")
+	}
+}
@@ -0,0 +1,113 @@
+package go_param_table
+
+import (
+	"slices"
+	"strings"
+)
+
+// Returns the chain of indexes that would form a cycle if idx became a derived value with
+// the given parents and outputs, starting and ending with idx, or nil if none would form.
+//
+// Only edges that could close a cycle through idx are followed: a walk starting at idx's
+// (future) successors that reaches one of its parents, or a value whose calculation also
+// writes one of its parents, means idx would eventually trigger itself
+func (t *ParamTableOf[E]) findCycle(idx E, parents []E, outputs []E) []E {
+	if slices.Contains(parents, idx) {
+		return []E{idx, idx}
+	}
+	for _, out := range outputs {
+		if slices.Contains(parents, out) {
+			return []E{idx, idx}
+		}
+	}
+	p := &t.prop
+	defer t.endPropagation()
+	// p.indeg holds the index each visited value was first reached from
+	visit := func(from E, child E) {
+		if p.marks[child]&_PROP_DIRTY == 0 {
+			p.marks[child] |= _PROP_DIRTY
+			p.indeg[child] = uint32(from)
+			p.dirty = append(p.dirty, child)
+			p.queue = append(p.queue, child)
+		}
+	}
+	for _, child := range t.getChildren(idx) {
+		visit(idx, child)
+	}
+	for _, out := range outputs {
+		if out == idx {
+			continue
+		}
+		for _, child := range t.getChildren(out) {
+			visit(idx, child)
+		}
+	}
+	for len(p.queue) > 0 {
+		n := p.queue[len(p.queue)-1]
+		p.queue = p.queue[:len(p.queue)-1]
+		if n == idx || t.writesAnyOf(n, parents) {
+			path := []E{idx}
+			if n != idx {
+				path = append(path, n)
+			}
+			for at := n; p.indeg[at] != uint32(idx); {
+				at = E(p.indeg[at])
+				path = append(path, at)
+			}
+			path = append(path, idx)
+			slices.Reverse(path)
+			return path
+		}
+		t.forEachSuccessor(n, func(child E) {
+			visit(n, child)
+		})
+	}
+	return nil
+}
+
+// Whether idx is one of vals, or idx's calculation writes one of vals
+func (t *ParamTableOf[E]) writesAnyOf(idx E, vals []E) bool {
+	if slices.Contains(vals, idx) {
+		return true
+	}
+	for _, sib := range t.getSiblings(idx) {
+		if slices.Contains(vals, sib) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *ParamTableOf[E]) cycleErr(idx E, parents []E, outputs []E) error {
+	path := t.findCycle(idx, parents, outputs)
+	if path == nil {
+		return nil
+	}
+	err := newParamError(ErrCycle, idx, "cyclic dependency: making index %s a derived value would create an infinite update loop: %s", t.paramLabel(uint32(idx)), t.formatIdxPath(path))
+	err.Path = widenIdxs(path)
+	return err
+}
+
+func (t *ParamTableOf[E]) formatIdxPath(path []E) string {
+	return t.formatIdxList(path, " -> ")
+}
+
+func (t *ParamTableOf[E]) formatIdxList(idxs []E, sep string) string {
+	var sb strings.Builder
+	for i, idx := range idxs {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(t.paramLabel(uint32(idx)))
+	}
+	return sb.String()
+}
+
+// Copies a list of table indexes into the index type of ParamError.Path
+func widenIdxs[E Index](idxs []E) []uint32 {
+	wide := make([]uint32, len(idxs))
+	for i, idx := range idxs {
+		wide[i] = uint32(idx)
+	}
+	return wide
+}
@@ -0,0 +1,59 @@
+package go_param_table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStaticCycleDetection(t *testing.T) {
+	t.Run("narrow", testStaticCycleDetection[uint16])
+	t.Run("wide", testStaticCycleDetection[uint32])
+}
+
+func testStaticCycleDetection[E Index](t *testing.T) {
+	var out bytes.Buffer
+	const (
+		A PIdx_F64 = iota
+		B
+		C
+		X
+		_F64_PARAMS_END
+	)
+	// cycles must be rejected even with debug checks disabled
+	table, _ := newF64TestTableOf[E](uint32(_F64_PARAMS_END), WithDebug(false), WithDebugWriter(&out))
+	table.InitRoot_F64(A, 1, false)
+	derive_F64(table, E(B), _TEST_CALC_ADD_ONE, E(A))
+	derive_F64(table, E(C), _TEST_CALC_ADD_ONE, E(B))
+
+	var expectCycle = func(name string, path string, init func()) {
+		t.Helper()
+		out.Reset()
+		defer func() {
+			t.Helper()
+			if r := recover(); r == nil {
+				t.Errorf("%s: cyclic InitDerived did not cause panic", name)
+			}
+			if !strings.Contains(out.String(), path) {
+				t.Errorf("%s: cycle report did not contain path %q:\n\t%s", name, path, out.String())
+			}
+		}()
+		init()
+	}
+	expectCycle("self input", "3 -> 3", func() {
+		table.InitDerived_F64(X, false, _TEST_CALC_SUM, []E{E(C), E(X)}, []E{E(X)})
+	})
+	// X writes A, which B and then C depend on, and X depends on C
+	expectCycle("through sibling output", "3 -> 1 -> 2 -> 3", func() {
+		table.InitDerived_F64(X, false, _TEST_CALC_SUM, []E{E(C)}, []E{E(X), E(A)})
+	})
+	if table.isDerived(E(X)) || len(table.getChildren(E(C))) != 0 {
+		t.Errorf("rejected InitDerived modified the table")
+	}
+	// the same edges without the cycle must still be accepted
+	table.InitDerived_F64(X, false, _TEST_CALC_SUM, []E{E(C)}, []E{E(X)})
+	table.SetRoot_F64(A, 10)
+	if got := table.Get_F64(X); got != 12 {
+		t.Errorf("value error:\n\tEXP: %f\n\tGOT: %f", 12.0, got)
+	}
+}
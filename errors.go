@@ -0,0 +1,64 @@
+package go_param_table
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors identifying each kind of table misuse. Every error returned by a Try*()
+// function (and every panic value raised by the safety checks) is a *ParamError wrapping
+// exactly one of these, so they can be matched with errors.Is()
+var (
+	ErrIndexOutOfRange       = errors.New("index out of range")
+	ErrWrongType             = errors.New("wrong parameter type")
+	ErrNotInitialized        = errors.New("parameter not initialized")
+	ErrDerivedNotSettable    = errors.New("derived parameter cannot be set directly")
+	ErrCalcOutOfRange        = errors.New("calc index out of range")
+	ErrCalcNotRegistered     = errors.New("calc not registered")
+	ErrCalcAlreadyRegistered = errors.New("calc already registered")
+	ErrTooManyHookups        = errors.New("too many calculation inputs or outputs")
+	ErrTooManyChildren       = errors.New("too many children")
+	ErrCycle                 = errors.New("cyclic dependency")
+	ErrLayout                = errors.New("invalid table layout")
+	ErrNoBatch               = errors.New("no batch open")
+	ErrBatchOpen             = errors.New("batch still open")
+	ErrDuplicateName         = errors.New("duplicate name")
+	ErrInvalidName           = errors.New("invalid parameter name")
+	ErrNotDerived            = errors.New("parameter is not derived")
+	ErrHasChildren           = errors.New("parameter has children")
+	ErrSharedOutput          = errors.New("parameter is an output of another calc")
+	ErrStaleSnapshot         = errors.New("snapshot does not match the dependency graph")
+	ErrFormat                = errors.New("invalid serialized table")
+	ErrUnknownName           = errors.New("unknown parameter name")
+	ErrValueRange            = errors.New("value out of range")
+	ErrNoFrontBuffer         = errors.New("front buffer not enabled")
+)
+
+// The structured error used for every safety check failure
+type ParamError struct {
+	// One of the Err* sentinel errors
+	Kind error
+	// The parameter (or calc, for calc errors) index the error concerns, or the null index of
+	// the table (PIDX_NULL or WIDE_PIDX_NULL) if none
+	Idx uint32
+	// For ErrCycle, the indexes involved in the cycle. Cycles rejected by InitDerived_*() list
+	// the full path in update order, starting and ending with the same index
+	Path []uint32
+	msg  string
+}
+
+func newParamError[I Index](kind error, idx I, format string, args ...any) *ParamError {
+	return &ParamError{
+		Kind: kind,
+		Idx:  uint32(idx),
+		msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *ParamError) Error() string {
+	return "go_param_table: " + e.msg
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Kind
+}
@@ -0,0 +1,78 @@
+package go_param_table
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestTryErrors(t *testing.T) {
+	t.Run("narrow", testTryErrors[uint16])
+	t.Run("wide", testTryErrors[uint32])
+}
+
+func testTryErrors[E Index](t *testing.T) {
+	const (
+		A PIdx_F64 = iota
+		B
+		C
+		_F64_PARAMS_END
+	)
+	const (
+		FlagIdx PIdx_Bool = PIdx_Bool(iota + _F64_PARAMS_END)
+		_BOOL_PARAMS_END
+	)
+	// Try*() variants must check regardless of the debug setting
+	table := newTestTableOf[E](ParamLayout{F64End: _F64_PARAMS_END, BoolEnd: _BOOL_PARAMS_END}, _TEST_CALC_COUNT,
+		WithDebug(false), WithDebugWriter(&bytes.Buffer{}))
+
+	var expectErr = func(name string, err error, kind error) {
+		t.Helper()
+		if !errors.Is(err, kind) {
+			t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", name, kind, err)
+		}
+		var perr *ParamError
+		if err != nil && !errors.As(err, &perr) {
+			t.Errorf("%s: error %v is not a *ParamError", name, err)
+		}
+	}
+	expectErr("register out of range", table.TryRegisterCalc(_TEST_CALC_COUNT, func(c *CalcInterfaceOf[E]) {}), ErrCalcOutOfRange)
+	expectErr("register", table.TryRegisterCalc(_TEST_CALC_ADD_ONE, func(c *CalcInterfaceOf[E]) {
+		c.SetOutput_F64(0, c.GetInput_F64(0)+1)
+	}), nil)
+	expectErr("register twice", table.TryRegisterCalc(_TEST_CALC_ADD_ONE, func(c *CalcInterfaceOf[E]) {}), ErrCalcAlreadyRegistered)
+	expectErr("init root", table.TryInitRoot_F64(A, 1, false), nil)
+	expectErr("init root wrong type", table.TryInitRoot_Bool(PIdx_Bool(A), true, false), ErrWrongType)
+	expectErr("init root wrong type", table.TryInitRoot_U64(PIdx_U64(FlagIdx), 1, false), ErrWrongType)
+	_, err := table.TryGet_F64(B)
+	expectErr("get uninit", err, ErrNotInitialized)
+	_, err = table.TryGet_F64(PIdx_F64(1000))
+	expectErr("get out of range", err, ErrIndexOutOfRange)
+	expectErr("set uninit", table.TrySetRoot_F64(B, 1), ErrNotInitialized)
+	expectErr("derive unregistered calc", table.TryInitDerived_F64(B, false, _TEST_CALC_SUM, []E{E(A)}, []E{E(B)}), ErrCalcNotRegistered)
+	expectErr("derive from uninit", table.TryInitDerived_F64(B, false, _TEST_CALC_ADD_ONE, []E{E(C)}, []E{E(B)}), ErrNotInitialized)
+	expectErr("derive too many", table.TryInitDerived_F64(B, false, _TEST_CALC_ADD_ONE, make([]E, maxParamsLen[E]()+1), []E{E(B)}), ErrTooManyHookups)
+	expectErr("derive cycle", table.TryInitDerived_F64(B, false, _TEST_CALC_ADD_ONE, []E{E(B)}, []E{E(B)}), ErrCycle)
+	if table.isDerived(E(B)) {
+		t.Errorf("failed TryInitDerived_F64() modified the table")
+	}
+	expectErr("derive", table.TryInitDerived_F64(B, false, _TEST_CALC_ADD_ONE, []E{E(A)}, []E{E(B)}), nil)
+	expectErr("set derived", table.TrySetRoot_F64(B, 1), ErrDerivedNotSettable)
+	expectErr("set root", table.TrySetRoot_F64(A, 5), nil)
+	if val, err := table.TryGet_F64(B); err != nil || val != 6 {
+		t.Errorf("value error:\n\tEXP: %f <nil>\n\tGOT: %f %v", 6.0, val, err)
+	}
+	_, err = tryNewParamTableOf[E](ParamLayout{U64End: 5, U32End: 1}.ends(), 0)
+	expectErr("bad layout", err, ErrLayout)
+
+	// remaining panics carry the same structured error
+	table.Configure(WithDebug(true))
+	func() {
+		defer func() {
+			r := recover()
+			err, _ := r.(error)
+			expectErr("panic value", err, ErrDerivedNotSettable)
+		}()
+		table.SetRoot_F64(B, 1)
+	}()
+}
@@ -0,0 +1,243 @@
+package go_param_table
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// The number of frames EnableFrontBuffer() allocates: the front frame, one still read by a
+// slow reader, and the one the next Publish() writes
+const _FRONT_FRAMES = 3
+
+// A copy of every value of the table, written by Publish() and only read once published
+type frame struct {
+	values []byte
+	ptrs   []unsafe.Pointer
+	strs   []string
+	vals   []any
+	flags  []paramFlags
+	// the number of FrontViews reading the frame, Publish() only writes frames no one reads
+	readers atomic.Int32
+}
+
+// The published frames of a table. Everything but front is only used by the goroutine
+// updating the table
+type frontBuffer struct {
+	front       atomic.Pointer[frame]
+	frames      []*frame
+	idxOffsets  [typeCount]uint32
+	byteOffsets [typeCount]uint32
+	paramCount  uint32
+	debug       bool
+}
+
+func (b *frontBuffer) memoryFootprint() uintptr {
+	if b == nil {
+		return 0
+	}
+	size := unsafe.Sizeof(*b)
+	size += uintptr(cap(b.frames)) * unsafe.Sizeof((*frame)(nil))
+	for _, f := range b.frames {
+		size += unsafe.Sizeof(*f)
+		size += uintptr(cap(f.values))
+		size += uintptr(cap(f.ptrs)) * uintptr(sizePtr)
+		size += uintptr(cap(f.strs)) * uintptr(sizeStr)
+		size += uintptr(cap(f.vals)) * uintptr(sizeVal)
+		size += uintptr(cap(f.flags)) * unsafe.Sizeof(paramFlags(0))
+	}
+	return size
+}
+
+// A stable, read-only view of the values of a table as of one Publish(), see Front(). It must
+// be released with Release() once it is no longer read
+type FrontView struct {
+	f *frame
+	b *frontBuffer
+}
+
+// Allocates the frames used by Publish() and Front() and publishes the current values. Tables
+// only pay for the frames once this is called. It must be called before any goroutine calls
+// Front(), and not again afterwards
+func (t *ParamTableOf[E]) EnableFrontBuffer() {
+	b := &frontBuffer{
+		idxOffsets:  t.idxOffsets,
+		byteOffsets: t.byteOffsets,
+		paramCount:  uint32(len(t.hookups)),
+		debug:       t.debug.enabled,
+	}
+	for i := 0; i < _FRONT_FRAMES; i += 1 {
+		b.frames = append(b.frames, t.newFrame())
+	}
+	t.front = b
+	t.Publish()
+}
+
+func (t *ParamTableOf[E]) newFrame() *frame {
+	return &frame{
+		values: make([]byte, len(t.values)),
+		ptrs:   make([]unsafe.Pointer, len(t.ptrs)),
+		strs:   make([]string, len(t.strs)),
+		vals:   make([]any, len(t.vals)),
+		flags:  make([]paramFlags, len(t.flags)),
+	}
+}
+
+// Copies every value into a frame no reader uses and atomically makes it the front frame, so
+// that every Front() from then on reads these values. Readers holding an older view keep
+// reading the values they started with. It only allocates when more readers hold old views
+// than there are spare frames.
+//
+// Call it from the goroutine updating the table, once an update is complete (for example once
+// per simulation tick). Panics if EnableFrontBuffer() was not called, or if a batch is open
+func (t *ParamTableOf[E]) Publish() {
+	t.checkNoBatch("Publish")
+	b := t.front
+	if b == nil {
+		t.fail(newParamError(ErrNoFrontBuffer, ^E(0), "Publish(): EnableFrontBuffer() was never called"))
+	}
+	front := b.front.Load()
+	var f *frame
+	for _, candidate := range b.frames {
+		if candidate != front && candidate.readers.Load() == 0 {
+			f = candidate
+			break
+		}
+	}
+	if f == nil {
+		f = t.newFrame()
+		b.frames = append(b.frames, f)
+	}
+	copy(f.values, t.values)
+	copy(f.ptrs, t.ptrs)
+	copy(f.strs, t.strs)
+	copy(f.vals, t.vals)
+	copy(f.flags, t.flags)
+	b.front.Store(f)
+}
+
+// Returns a view of the values as of the last Publish(). Unlike every other method of a table,
+// it may be called from any goroutine, concurrently with updates of the table, and never waits
+// for them. Panics if EnableFrontBuffer() was not called
+func (t *ParamTableOf[E]) Front() FrontView {
+	b := t.front
+	if b == nil {
+		panic(newParamError(ErrNoFrontBuffer, ^E(0), "Front(): EnableFrontBuffer() was never called"))
+	}
+	for {
+		f := b.front.Load()
+		f.readers.Add(1)
+		// Publish() may have chosen f to write into before the reader was counted, in which case
+		// it is no longer the front frame
+		if b.front.Load() == f {
+			return FrontView{f: f, b: b}
+		}
+		f.readers.Add(-1)
+	}
+}
+
+// Ends the view, letting Publish() reuse its frame. The view must not be used afterwards.
+// Calling Release() more than once has no effect
+func (v *FrontView) Release() {
+	if v.f != nil {
+		v.f.readers.Add(-1)
+		v.f = nil
+	}
+}
+
+// Whether the parameter had been initialized when the view was published
+func (v *FrontView) IsInit(idx uint32) bool {
+	return idx < v.b.paramCount && getFlag(idx, v.f.flags).IsInit()
+}
+
+// Returns the type region of the parameter
+func (v *FrontView) TypeOf(idx uint32) ParamType {
+	if idx >= v.b.paramCount {
+		panic(newParamError(ErrIndexOutOfRange, idx, "index %d is outside bounds of parameter list (len %d)", idx, v.b.paramCount))
+	}
+	return ParamType(typeOfIdxIn(&v.b.idxOffsets, idx))
+}
+
+// Returns a published opaque value, see GetVal()
+func FrontVal[T any](v *FrontView, p PIdx_Val[T]) T {
+	val, _ := frontGet[any](v, uint32(p), typeVal).(T)
+	return val
+}
+
+// Returns a published value of the type. When debug checks are enabled for the table, the
+// index is checked to be of the type and initialized
+func frontGet[T any](v *FrontView, idx uint32, typeIdx int) T {
+	b := v.b
+	if b.debug {
+		if idx >= b.paramCount {
+			panic(newParamError(ErrIndexOutOfRange, idx, "index %d is outside bounds of parameter list (len %d)", idx, b.paramCount))
+		}
+		if typeOfIdxIn(&b.idxOffsets, idx) != typeIdx {
+			panic(newParamError(ErrWrongType, idx, "index %d is not a %s value", idx, typeNames[typeIdx]))
+		}
+		if !getFlag(idx, v.f.flags).IsInit() {
+			panic(newParamError(ErrNotInitialized, idx, "parameter index %d was never initialized", idx))
+		}
+	}
+	subIdx := idx - b.idxOffsets[typeIdx]
+	switch typeIdx {
+	case typePtr:
+		return *(*T)(unsafe.Pointer(&v.f.ptrs[subIdx]))
+	case typeStr:
+		return *(*T)(unsafe.Pointer(&v.f.strs[subIdx]))
+	case typeVal:
+		return *(*T)(unsafe.Pointer(&v.f.vals[subIdx]))
+	}
+	return *(*T)(unsafe.Pointer(&v.f.values[b.byteOffsets[typeIdx]+subIdx*sizeTable[typeIdx]]))
+}
+
+func (v *FrontView) Get_U8(idx PIdx_U8) uint8 {
+	return frontGet[uint8](v, uint32(idx), typeU8)
+}
+
+func (v *FrontView) Get_I8(idx PIdx_I8) int8 {
+	return frontGet[int8](v, uint32(idx), typeI8)
+}
+
+func (v *FrontView) Get_Bool(idx PIdx_Bool) bool {
+	return frontGet[bool](v, uint32(idx), typeBool)
+}
+
+func (v *FrontView) Get_U16(idx PIdx_U16) uint16 {
+	return frontGet[uint16](v, uint32(idx), typeU16)
+}
+
+func (v *FrontView) Get_I16(idx PIdx_I16) int16 {
+	return frontGet[int16](v, uint32(idx), typeI16)
+}
+
+func (v *FrontView) Get_U32(idx PIdx_U32) uint32 {
+	return frontGet[uint32](v, uint32(idx), typeU32)
+}
+
+func (v *FrontView) Get_I32(idx PIdx_I32) int32 {
+	return frontGet[int32](v, uint32(idx), typeI32)
+}
+
+func (v *FrontView) Get_F32(idx PIdx_F32) float32 {
+	return frontGet[float32](v, uint32(idx), typeF32)
+}
+
+func (v *FrontView) Get_U64(idx PIdx_U64) uint64 {
+	return frontGet[uint64](v, uint32(idx), typeU64)
+}
+
+func (v *FrontView) Get_I64(idx PIdx_I64) int64 {
+	return frontGet[int64](v, uint32(idx), typeI64)
+}
+
+func (v *FrontView) Get_F64(idx PIdx_F64) float64 {
+	return frontGet[float64](v, uint32(idx), typeF64)
+}
+
+func (v *FrontView) Get_Ptr(idx PIdx_Ptr) unsafe.Pointer {
+	return frontGet[unsafe.Pointer](v, uint32(idx), typePtr)
+}
+
+func (v *FrontView) Get_Str(idx PIdx_Str) string {
+	return frontGet[string](v, uint32(idx), typeStr)
+}
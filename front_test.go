@@ -0,0 +1,170 @@
+package go_param_table
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// 0 + 1 -> 2 -> 3
+func newFrontTestTable[E Index](tb testing.TB) *ParamTableOf[E] {
+	tb.Helper()
+	table, _ := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 1, false)
+	table.InitRoot_F64(1, 2, false)
+	derive_F64(table, 2, _TEST_CALC_SUM, 0, 1)
+	derive_F64(table, 3, _TEST_CALC_ADD_ONE, 2)
+	return table
+}
+
+func TestFrontBuffer(t *testing.T) {
+	t.Run("narrow", testFrontBuffer[uint16])
+	t.Run("wide", testFrontBuffer[uint32])
+}
+
+func testFrontBuffer[E Index](t *testing.T) {
+	table := newFrontTestTable[E](t)
+	expectPanic := func(name string, kind error, fn func()) {
+		t.Helper()
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, kind) {
+				t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", name, kind, err)
+			}
+		}()
+		fn()
+	}
+	expectPanic("front before enable", ErrNoFrontBuffer, func() { table.Front() })
+	table.EnableFrontBuffer()
+
+	old := table.Front()
+	table.SetRoot_F64(0, 10)
+	if got := old.Get_F64(3); got != 4 {
+		t.Errorf("unpublished change visible:\n\tEXP: 4\n\tGOT: %f", got)
+	}
+	table.Publish()
+	current := table.Front()
+	if old.Get_F64(3) != 4 || current.Get_F64(3) != 13 || !current.IsInit(2) || current.TypeOf(2) != TypeF64 {
+		t.Errorf("publish:\n\tEXP: old view 4, new view 13\n\tGOT: %f, %f", old.Get_F64(3), current.Get_F64(3))
+	}
+	old.Release()
+	old.Release()
+	current.Release()
+
+	// released frames are reused, frames still read are not
+	for i := 0; i < 10; i += 1 {
+		table.Publish()
+	}
+	if len(table.front.frames) != _FRONT_FRAMES {
+		t.Errorf("released frames not reused: %d frames", len(table.front.frames))
+	}
+	var held []FrontView
+	for i := 0; i < _FRONT_FRAMES; i += 1 {
+		held = append(held, table.Front())
+		table.SetRoot_F64(0, float64(i))
+		table.Publish()
+	}
+	for i := range held {
+		if got := held[i].Get_F64(0); got != float64(i-1) && i > 0 {
+			t.Errorf("held view %d overwritten:\n\tEXP: %d\n\tGOT: %f", i, i-1, got)
+		}
+		held[i].Release()
+	}
+	if len(table.front.frames) != _FRONT_FRAMES+1 {
+		t.Errorf("frames held by readers:\n\tEXP: %d frames\n\tGOT: %d", _FRONT_FRAMES+1, len(table.front.frames))
+	}
+
+	view := table.Front()
+	expectPanic("wrong type", ErrWrongType, func() { view.Get_U8(0) })
+	view.Release()
+	table.BeginBatch()
+	expectPanic("publish in batch", ErrBatchOpen, table.Publish)
+	table.Rollback()
+	if clone := table.Clone(); clone.front != nil {
+		t.Errorf("front buffer copied to a clone")
+	}
+}
+
+func TestFrontBufferStress(t *testing.T) {
+	t.Run("narrow", testFrontBufferStress[uint16])
+	t.Run("wide", testFrontBufferStress[uint32])
+}
+
+func testFrontBufferStress[E Index](t *testing.T) {
+	table := newFrontTestTable[E](t)
+	table.EnableFrontBuffer()
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r += 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				view := table.Front()
+				a, b, sum, next := view.Get_F64(0), view.Get_F64(1), view.Get_F64(2), view.Get_F64(3)
+				view.Release()
+				if sum != a+b || next != sum+1 {
+					t.Errorf("read a partially updated frame: %f + %f = %f, + 1 = %f", a, b, sum, next)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 2000; i += 1 {
+		table.BeginBatch()
+		table.SetRoot_F64(0, float64(i))
+		table.SetRoot_F64(1, float64(-2*i))
+		table.CommitBatch()
+		table.Publish()
+	}
+	stop.Store(true)
+	wg.Wait()
+}
+
+// Reads while another goroutine keeps updating, with the front buffer and with SyncParamTable
+func BenchmarkFrontRead(b *testing.B) {
+	table := newFrontTestTable[uint16](b)
+	table.EnableFrontBuffer()
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		for i := 0; !stop.Load(); i += 1 {
+			table.SetRoot_F64(0, float64(i))
+			table.Publish()
+		}
+		close(done)
+	}()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			view := table.Front()
+			_ = view.Get_F64(3)
+			view.Release()
+		}
+	})
+	b.StopTimer()
+	stop.Store(true)
+	<-done
+}
+
+func BenchmarkSyncRead(b *testing.B) {
+	s := NewSyncParamTable(newFrontTestTable[uint16](b))
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		for i := 0; !stop.Load(); i += 1 {
+			s.SetRoot_F64(0, float64(i))
+		}
+		close(done)
+	}()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = s.Get_F64(3)
+		}
+	})
+	b.StopTimer()
+	stop.Store(true)
+	<-done
+}
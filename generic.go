@@ -0,0 +1,175 @@
+package go_param_table
+
+import (
+	"unsafe"
+)
+
+// The value types a parameter can hold
+type Scalar interface {
+	uint8 | int8 | bool | uint16 | int16 | uint32 | int32 | float32 | uint64 | int64 | float64 | unsafe.Pointer | string
+}
+
+// A parameter index that can only be used with values of type T.
+//
+// The PIdx_* index types are aliases of Param, so passing (for example) a PIdx_U32 where a
+// uint64 parameter is expected fails to compile
+type Param[T Scalar] uint32
+
+// Any typed parameter index, used where parameters of different types are listed together
+type AnyParam interface {
+	Index() uint32
+	typeIdx() int
+}
+
+func (p Param[T]) Index() uint32 {
+	return uint32(p)
+}
+
+func (p Param[T]) typeIdx() int {
+	return typeIdxOf[T]()
+}
+
+var typeNames = [typeCount]string{
+	typeU64:  "Uint64",
+	typeI64:  "Int64",
+	typeF64:  "Float64",
+	typePtr:  "unsafe.Pointer",
+	typeStr:  "String",
+	typeVal:  "Value",
+	typeU32:  "Uint32",
+	typeI32:  "Int32",
+	typeF32:  "Float32",
+	typeU16:  "Uint16",
+	typeI16:  "Int16",
+	typeU8:   "Uint8",
+	typeI8:   "Int8",
+	typeBool: "Bool",
+}
+
+func typeIdxOf[T Scalar]() int {
+	var zero T
+	switch any(zero).(type) {
+	case uint64:
+		return typeU64
+	case int64:
+		return typeI64
+	case float64:
+		return typeF64
+	case unsafe.Pointer:
+		return typePtr
+	case string:
+		return typeStr
+	case uint32:
+		return typeU32
+	case int32:
+		return typeI32
+	case float32:
+		return typeF32
+	case uint16:
+		return typeU16
+	case int16:
+		return typeI16
+	case uint8:
+		return typeU8
+	case int8:
+		return typeI8
+	default:
+		return typeBool
+	}
+}
+
+func (t *ParamTableOf[E]) checkIdxOfType(idx uint32, typeIdx int, canBeDerived bool) {
+	t.checkIdxType(idx, typeNames[typeIdx], typeIdx, typeIdx == typeBool, canBeDerived)
+}
+
+// Returns the current value of the parameter
+func Get[T Scalar, E Index](t *ParamTableOf[E], p Param[T]) T {
+	idx := uint32(p)
+	typeIdx := typeIdxOf[T]()
+	t.checkIdxOfType(idx, typeIdx, true)
+	t.checkInit(idx)
+	memPtr, _ := t.getBytePtr(idx, typeIdx)
+	return *(*T)(unsafe.Pointer(memPtr))
+}
+
+// Sets the value of a root parameter and updates all values derived from it
+// (or stages it until CommitBatch() if a batch is open)
+func Set[T Scalar, E Index](t *ParamTableOf[E], p Param[T], val T) {
+	idx := uint32(p)
+	typeIdx := typeIdxOf[T]()
+	t.checkInit(idx)
+	if t.history.wrapsSet(len(t.batch.starts)) {
+		// recorded edits always go through a batch, whose commit records them
+		t.BeginBatch()
+		Set(t, p, val)
+		t.CommitBatch()
+		return
+	}
+	t.stageRoot(idx, typeIdx)
+	if setValue(t, idx, typeIdx, val, false) {
+		t.rootChanged(E(idx))
+	}
+}
+
+// Initializes a root parameter with its first value. Panics if a batch is open, which could not
+// roll the initialization back. Re-initializing a root while the undo history is enabled is
+// recorded like Set()
+func InitRoot[T Scalar, E Index](t *ParamTableOf[E], p Param[T], val T, alwaysUpdate bool) {
+	idx := uint32(p)
+	typeIdx := typeIdxOf[T]()
+	t.checkNoBatch("InitRoot")
+	wasInit := getFlag(idx, t.flags).IsInit()
+	f := _PFLAG_INIT
+	if alwaysUpdate {
+		f |= _PFLAG_ALWAYS_UPDATE
+	}
+	setFlag(idx, t.flags, f)
+	if wasInit && t.history.wrapsSet(0) {
+		Set(t, p, val)
+		return
+	}
+	if setValue(t, idx, typeIdx, val, false) {
+		t.propagateFrom(E(idx))
+	}
+}
+
+// Initializes a derived parameter, calculated by calcIdx from inputs and written to outputs
+// (which normally includes p itself). When debug checks are enabled, every input and output
+// is also checked to be of the type its index was declared with
+func InitDerived[T Scalar, E Index](t *ParamTableOf[E], p Param[T], alwaysUpdate bool, calcIdx PIdx_Calc, inputs []AnyParam, outputs []AnyParam) {
+	idx := uint32(p)
+	t.checkIdxOfType(idx, typeIdxOf[T](), true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, t.anyParamIdxs(inputs), t.anyParamIdxs(outputs))
+}
+
+func (t *ParamTableOf[E]) anyParamIdxs(params []AnyParam) []E {
+	idxs := make([]E, len(params))
+	for i, p := range params {
+		t.checkIdxOfType(p.Index(), p.typeIdx(), true)
+		idxs[i] = E(p.Index())
+	}
+	return idxs
+}
+
+// Returns the value of a calculation input
+func Input[T Scalar, E Index](c *CalcInterfaceOf[E], inputIdx uint16) T {
+	return Get(c.table, Param[T](c.inputs[inputIdx]))
+}
+
+// Sets the value of a calculation output
+func Output[T Scalar, E Index](c *CalcInterfaceOf[E], outputIdx uint16, val T) {
+	idx := c.outputs[outputIdx]
+	if setValue(c.table, uint32(idx), typeIdxOf[T](), val, true) {
+		c.markChanged(idx)
+	}
+}
+
+func setValue[T Scalar, E Index](t *ParamTableOf[E], idx uint32, typeIdx int, val T, canBeDerived bool) (changed bool) {
+	t.checkIdxOfType(idx, typeIdx, canBeDerived)
+	f := getFlag(idx, t.flags)
+	memPtr, _ := t.getBytePtr(idx, typeIdx)
+	valPtr := (*T)(unsafe.Pointer(memPtr))
+	oldVal := *valPtr
+	*valPtr = val
+	return f.AlwaysUpdate() || oldVal != val
+}
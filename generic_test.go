@@ -0,0 +1,64 @@
+package go_param_table
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenericAPI(t *testing.T) {
+	t.Run("narrow", testGenericAPI[uint16])
+	t.Run("wide", testGenericAPI[uint32])
+}
+
+func testGenericAPI[E Index](t *testing.T) {
+	const (
+		Total PIdx_U64 = PIdx_U64(iota)
+		_U64_PARAMS_END
+	)
+	const (
+		Width PIdx_F32 = PIdx_F32(iota + _U64_PARAMS_END)
+		Height
+		_F32_PARAMS_END
+	)
+	const (
+		Count PIdx_U16 = PIdx_U16(iota + _F32_PARAMS_END)
+		_U16_PARAMS_END
+	)
+	const (
+		CalcTotal PIdx_Calc = iota
+		_CALC_COUNT
+	)
+	table := newTestTableOf[E](ParamLayout{U64End: _U64_PARAMS_END, F32End: _F32_PARAMS_END, U16End: _U16_PARAMS_END}, _CALC_COUNT,
+		WithDebug(true), WithVerbosity(VerbositySilent))
+	table.RegisterCalc(CalcTotal, func(c *CalcInterfaceOf[E]) {
+		w := Input[float32](c, 0)
+		h := Input[float32](c, 1)
+		n := Input[uint16](c, 2)
+		Output(c, 0, uint64(w*h)*uint64(n))
+	})
+	InitRoot(&table, Width, 2, false)
+	InitRoot(&table, Height, 3, false)
+	InitRoot(&table, Count, 4, false)
+	InitDerived(&table, Total, false, CalcTotal, []AnyParam{Width, Height, Count}, []AnyParam{Total})
+	if got := Get(&table, Total); got != 24 {
+		t.Errorf("value error:\n\tEXP: %d\n\tGOT: %d", 24, got)
+	}
+	Set(&table, Height, 10)
+	if got := table.Get_U64(Total); got != 80 {
+		t.Errorf("value error:\n\tEXP: %d\n\tGOT: %d", 80, got)
+	}
+	if got := table.Get_F32(Height); got != Get(&table, Height) || got != 10 {
+		t.Errorf("typed wrapper and generic Get() disagree: %f != %f", got, Get(&table, Height))
+	}
+
+	// an index declared with the wrong type is caught even though it is passed as AnyParam
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrWrongType) {
+				t.Errorf("mistyped derived input:\n\tEXP: %v\n\tGOT: %v", ErrWrongType, err)
+			}
+		}()
+		InitDerived(&table, Total, false, CalcTotal, []AnyParam{Width, PIdx_U64(Height), Count}, []AnyParam{Total})
+	}()
+}
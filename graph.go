@@ -0,0 +1,231 @@
+package go_param_table
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// An option passed to WriteDOT() or WriteMermaid()
+type GraphOption func(g *graphConfig)
+
+type graphConfig struct {
+	ancestorsOf   []uint32
+	descendantsOf []uint32
+}
+
+// Restricts the graph to idx and every value it is calculated from. Combined with other
+// focus options, the union of all of them is written
+func WithAncestorsOf[I Index](idx I) GraphOption {
+	return func(g *graphConfig) {
+		g.ancestorsOf = append(g.ancestorsOf, uint32(idx))
+	}
+}
+
+// Restricts the graph to idx and every value that may be recalculated when it changes.
+// Combined with other focus options, the union of all of them is written
+func WithDescendantsOf[I Index](idx I) GraphOption {
+	return func(g *graphConfig) {
+		g.descendantsOf = append(g.descendantsOf, uint32(idx))
+	}
+}
+
+// One calc evaluation: the hookup of the derived value that owns it
+type graphCalc[E Index] struct {
+	owner   E
+	calcIdx PIdx_Calc
+	inputs  []E
+	outputs []E
+}
+
+// Writes the table as a Graphviz DOT digraph. Every initialized parameter is a node labelled with
+// its index, name, type, current value and whether it is a root or derived value. Every calc is
+// a box node with edges from its inputs and to its outputs
+func (t *ParamTableOf[E]) WriteDOT(w io.Writer, opts ...GraphOption) error {
+	selected, err := t.graphSelection(opts)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("digraph ParamTable {\n\trankdir=LR;\n\tnode [fontname=\"monospace\"];\n")
+	for idx, ok := range selected {
+		if !ok {
+			continue
+		}
+		shape := "ellipse"
+		if !t.isDerived(E(idx)) {
+			shape = "box, style=rounded"
+		}
+		fmt.Fprintf(&buf, "\tp%d [shape=%s, label=\"%s\"];\n", idx, shape, dotEscape(strings.Join(t.graphNodeLines(uint32(idx)), "\n")))
+	}
+	for _, c := range t.graphCalcs(selected) {
+		fmt.Fprintf(&buf, "\tc%d [shape=box, label=\"%s\"];\n", c.owner, dotEscape("calc "+t.calcLabel(c.calcIdx)))
+		for _, in := range c.inputs {
+			if selected[in] {
+				fmt.Fprintf(&buf, "\tp%d -> c%d;\n", in, c.owner)
+			}
+		}
+		for _, out := range c.outputs {
+			if selected[out] {
+				fmt.Fprintf(&buf, "\tc%d -> p%d;\n", c.owner, out)
+			}
+		}
+	}
+	buf.WriteString("}\n")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Writes the table as a Mermaid flowchart, with the same nodes and edges as WriteDOT()
+func (t *ParamTableOf[E]) WriteMermaid(w io.Writer, opts ...GraphOption) error {
+	selected, err := t.graphSelection(opts)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("flowchart LR\n")
+	for idx, ok := range selected {
+		if !ok {
+			continue
+		}
+		open, close := "([", "])"
+		if !t.isDerived(E(idx)) {
+			open, close = "[", "]"
+		}
+		fmt.Fprintf(&buf, "\tp%d%s\"%s\"%s\n", idx, open, mermaidEscape(strings.Join(t.graphNodeLines(uint32(idx)), "<br/>")), close)
+	}
+	for _, c := range t.graphCalcs(selected) {
+		fmt.Fprintf(&buf, "\tc%d{{\"%s\"}}\n", c.owner, mermaidEscape("calc "+t.calcLabel(c.calcIdx)))
+		for _, in := range c.inputs {
+			if selected[in] {
+				fmt.Fprintf(&buf, "\tp%d --> c%d\n", in, c.owner)
+			}
+		}
+		for _, out := range c.outputs {
+			if selected[out] {
+				fmt.Fprintf(&buf, "\tc%d --> p%d\n", c.owner, out)
+			}
+		}
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Returns which parameters are written: all initialized ones, or the union of the focus options
+func (t *ParamTableOf[E]) graphSelection(opts []GraphOption) ([]bool, error) {
+	var config graphConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	selected := make([]bool, len(t.hookups))
+	if len(config.ancestorsOf) == 0 && len(config.descendantsOf) == 0 {
+		for idx := range selected {
+			selected[idx] = getFlag(uint32(idx), t.flags).IsInit()
+		}
+		return selected, nil
+	}
+	for _, focus := range [2]struct {
+		idxs []uint32
+		walk func(idx E) []E
+	}{{config.ancestorsOf, t.ancestors}, {config.descendantsOf, t.descendants}} {
+		for _, idx := range focus.idxs {
+			if int(idx) >= len(t.hookups) {
+				return nil, newParamError(ErrIndexOutOfRange, idx, "graph focus index %d is outside bounds of parameter list (len %d)", idx, len(t.hookups))
+			}
+			selected[idx] = true
+			for _, related := range focus.walk(E(idx)) {
+				selected[related] = true
+			}
+		}
+	}
+	return selected, nil
+}
+
+// Returns every calc writing at least one selected value. Derived values initialized with the
+// same calc, inputs and outputs share one evaluation, so only the first of them is returned
+func (t *ParamTableOf[E]) graphCalcs(selected []bool) []graphCalc[E] {
+	var calcs []graphCalc[E]
+	for idx := range t.hookups {
+		owner := E(idx)
+		if !t.isDerived(owner) {
+			continue
+		}
+		outputs := t.getSiblings(owner)
+		shared, anySelected := false, selected[owner]
+		for _, out := range outputs {
+			anySelected = anySelected || selected[out]
+			if out < owner && t.sameCalc(out, owner) {
+				shared = true
+			}
+		}
+		if shared || !anySelected {
+			continue
+		}
+		calcs = append(calcs, graphCalc[E]{
+			owner:   owner,
+			calcIdx: PIdx_Calc(t.hookupData[uint32(t.hookups[owner])+_HOOK_OFF_CALC]),
+			inputs:  t.getParents(owner),
+			outputs: outputs,
+		})
+	}
+	return calcs
+}
+
+func (t *ParamTableOf[E]) graphNodeLines(idx uint32) []string {
+	typeIdx := t.typeOfIdx(idx)
+	lines := []string{t.paramLabel(idx)}
+	if !getFlag(idx, t.flags).IsInit() {
+		return append(lines, typeNames[typeIdx], "uninitialized")
+	}
+	lines = append(lines, typeNames[typeIdx]+" = "+t.formatValue(idx, typeIdx))
+	if t.isDerived(E(idx)) {
+		return append(lines, "derived")
+	}
+	return append(lines, "root")
+}
+
+func (t *ParamTableOf[E]) formatValue(idx uint32, typeIdx int) string {
+	memPtr, _ := t.getBytePtr(idx, typeIdx)
+	ptr := unsafe.Pointer(memPtr)
+	switch typeIdx {
+	case typeU64:
+		return fmt.Sprint(*(*uint64)(ptr))
+	case typeI64:
+		return fmt.Sprint(*(*int64)(ptr))
+	case typeF64:
+		return fmt.Sprint(*(*float64)(ptr))
+	case typePtr:
+		return fmt.Sprint(*(*unsafe.Pointer)(ptr))
+	case typeStr:
+		return strconv.Quote(*(*string)(ptr))
+	case typeVal:
+		return fmt.Sprint(*(*any)(ptr))
+	case typeU32:
+		return fmt.Sprint(*(*uint32)(ptr))
+	case typeI32:
+		return fmt.Sprint(*(*int32)(ptr))
+	case typeF32:
+		return fmt.Sprint(*(*float32)(ptr))
+	case typeU16:
+		return fmt.Sprint(*(*uint16)(ptr))
+	case typeI16:
+		return fmt.Sprint(*(*int16)(ptr))
+	case typeU8:
+		return fmt.Sprint(*(*uint8)(ptr))
+	case typeI8:
+		return fmt.Sprint(*(*int8)(ptr))
+	default:
+		return fmt.Sprint(*(*bool)(ptr))
+	}
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
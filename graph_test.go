@@ -0,0 +1,81 @@
+package go_param_table
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGraphExport(t *testing.T) {
+	t.Run("narrow", testGraphExport[uint16])
+	t.Run("wide", testGraphExport[uint32])
+}
+
+func testGraphExport[E Index](t *testing.T) {
+	const (
+		Root PIdx_F64 = iota
+		Left
+		Right
+		Bottom
+		Other
+		Unused
+		_F64_PARAMS_END
+	)
+	table, _ := newF64TestTableOf[E](uint32(_F64_PARAMS_END), WithDebug(true))
+	table.SetMeta(E(Root), ParamMeta{Name: `root "top"`})
+	table.InitRoot_F64(Root, 1, false)
+	table.InitRoot_F64(Other, 4, false)
+	derive_F64(table, E(Left), _TEST_CALC_ADD_ONE, E(Root))
+	derive_F64(table, E(Right), _TEST_CALC_DOUBLE, E(Root))
+	derive_F64(table, E(Bottom), _TEST_CALC_SUM, E(Left), E(Right), E(Other))
+
+	var expectLines = func(name string, out string, want []string, unwanted []string) {
+		t.Helper()
+		for _, line := range want {
+			if !strings.Contains(out, line) {
+				t.Errorf("%s: missing %q in:\n%s", name, line, out)
+			}
+		}
+		for _, line := range unwanted {
+			if strings.Contains(out, line) {
+				t.Errorf("%s: unexpected %q in:\n%s", name, line, out)
+			}
+		}
+	}
+
+	var dot bytes.Buffer
+	if err := table.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	expectLines("dot", dot.String(), []string{
+		`digraph ParamTable {`,
+		`p0 [shape=box, style=rounded, label="0 (root \"top\")\nFloat64 = 1\nroot"];`,
+		`p3 [shape=ellipse, label="3\nFloat64 = 8\nderived"];`,
+		`c3 [shape=box, label="calc 2"];`,
+		`p0 -> c1;`, `c1 -> p1;`, `p1 -> c3;`, `p2 -> c3;`, `p4 -> c3;`, `c3 -> p3;`,
+	}, []string{"p5 "})
+
+	var mermaid bytes.Buffer
+	if err := table.WriteMermaid(&mermaid, WithAncestorsOf(E(Left))); err != nil {
+		t.Fatal(err)
+	}
+	expectLines("mermaid ancestors", mermaid.String(), []string{
+		"flowchart LR",
+		`p0["0 (root #quot;top#quot;)<br/>Float64 = 1<br/>root"]`,
+		`p1(["1<br/>Float64 = 2<br/>derived"])`,
+		"p0 --> c1", "c1 --> p1",
+	}, []string{"p2", "p3", "p4", "c3"})
+
+	mermaid.Reset()
+	if err := table.WriteMermaid(&mermaid, WithDescendantsOf(E(Right)), WithAncestorsOf(E(Other))); err != nil {
+		t.Fatal(err)
+	}
+	expectLines("mermaid descendants", mermaid.String(), []string{
+		"p2 --> c3", "p4 --> c3", "c3 --> p3",
+	}, []string{"p0", "p1", "c1"})
+
+	if err := table.WriteDOT(&dot, WithDescendantsOf(E(100))); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("out of range focus:\n\tEXP: %v\n\tGOT: %v", ErrIndexOutOfRange, err)
+	}
+}
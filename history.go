@@ -0,0 +1,248 @@
+package go_param_table
+
+import (
+	"slices"
+	"time"
+	"unsafe"
+)
+
+// An option passed to EnableHistory()
+type HistoryOption func(h *historyConfig)
+
+type historyConfig struct {
+	depth  int
+	window time.Duration
+}
+
+// The number of entries EnableHistory() keeps by default
+const DefaultHistoryDepth = 100
+
+// Sets the maximum number of undoable entries, the oldest entry is dropped when a new one would
+// exceed it. Values below 1 are treated as 1
+func WithHistoryDepth(depth int) HistoryOption {
+	return func(h *historyConfig) {
+		h.depth = max(depth, 1)
+	}
+}
+
+// Merges an edit of a single root into the previous entry if that entry only changed the same
+// root and was last updated less than window ago, so that (for example) every step of a slider
+// drag is undone at once. Defaults to 0, which never merges entries. See also SealHistory()
+func WithCoalesceWindow(window time.Duration) HistoryOption {
+	return func(h *historyConfig) {
+		h.window = window
+	}
+}
+
+// The undo history of a table. Only allocated once EnableHistory() is called, so tables that
+// never use it pay for one nil pointer only
+type historyState struct {
+	config  historyConfig
+	entries []historyEntry
+	// entries[:pos] can be undone, entries[pos:] redone
+	pos int
+	// changes recorded by the current batch commit, not yet turned into an entry
+	pending []historyChange
+	// set while Undo() or Redo() write roots, which must not be recorded again
+	applying bool
+	// the next entry must not be merged into the last one
+	sealed bool
+}
+
+// Every root changed by one SetRoot_*() call outside a batch, or by one outermost batch
+type historyEntry struct {
+	changes []historyChange
+	at      time.Time
+}
+
+type historyChange struct {
+	idx     uint32
+	typeIdx uint8
+	old     savedValue
+	new     savedValue
+}
+
+// Whether a SetRoot_*() call must be wrapped in a batch to be recorded
+func (h *historyState) wrapsSet(batchDepth int) bool {
+	return h != nil && !h.applying && batchDepth == 0
+}
+
+func (h *historyState) recordChange(e *batchEntry, current savedValue) {
+	if h.applying {
+		return
+	}
+	h.pending = append(h.pending, historyChange{idx: e.idx, typeIdx: e.typeIdx, old: e.old, new: current})
+}
+
+// Turns the changes recorded by a batch commit into an undoable entry, discarding every
+// entry that could still be redone
+func (h *historyState) commitEntry() {
+	if len(h.pending) == 0 {
+		return
+	}
+	now := time.Now()
+	clear(h.entries[h.pos:])
+	h.entries = h.entries[:h.pos]
+	if h.coalesces(now) {
+		last := &h.entries[len(h.entries)-1]
+		last.changes[0].new = h.pending[0].new
+		last.at = now
+	} else {
+		h.entries = append(h.entries, historyEntry{changes: slices.Clone(h.pending), at: now})
+		if drop := len(h.entries) - h.config.depth; drop > 0 {
+			copy(h.entries, h.entries[drop:])
+			clear(h.entries[len(h.entries)-drop:])
+			h.entries = h.entries[:len(h.entries)-drop]
+		}
+	}
+	h.pos = len(h.entries)
+	h.sealed = false
+	clear(h.pending)
+	h.pending = h.pending[:0]
+}
+
+func (h *historyState) coalesces(now time.Time) bool {
+	if h.sealed || h.config.window <= 0 || len(h.pending) != 1 || len(h.entries) == 0 {
+		return false
+	}
+	last := h.entries[len(h.entries)-1]
+	return len(last.changes) == 1 && last.changes[0].idx == h.pending[0].idx && now.Sub(last.at) < h.config.window
+}
+
+func (h *historyState) memoryFootprint() uintptr {
+	if h == nil {
+		return 0
+	}
+	size := unsafe.Sizeof(*h)
+	size += uintptr(cap(h.entries)) * unsafe.Sizeof(historyEntry{})
+	size += uintptr(cap(h.pending)) * unsafe.Sizeof(historyChange{})
+	for _, entry := range h.entries {
+		size += uintptr(cap(entry.changes)) * unsafe.Sizeof(historyChange{})
+	}
+	return size
+}
+
+func (h *historyState) clone() *historyState {
+	if h == nil {
+		return nil
+	}
+	clone := *h
+	clone.entries = slices.Clone(h.entries)
+	for i := range clone.entries {
+		clone.entries[i].changes = slices.Clone(h.entries[i].changes)
+	}
+	clone.pending = nil
+	return &clone
+}
+
+// Starts recording every change of a root value (through SetRoot_*(), Set() or SetVal()) so it
+// can be undone with Undo() and redone with Redo(). All roots changed within one outermost batch
+// form a single entry. Calling it again only changes the options, keeping the recorded entries
+func (t *ParamTableOf[E]) EnableHistory(opts ...HistoryOption) {
+	if t.history == nil {
+		t.history = &historyState{config: historyConfig{depth: DefaultHistoryDepth}}
+	}
+	for _, opt := range opts {
+		opt(&t.history.config)
+	}
+	if drop := len(t.history.entries) - t.history.config.depth; drop > 0 {
+		t.history.entries = slices.Delete(t.history.entries, 0, drop)
+		t.history.pos = max(t.history.pos-drop, 0)
+	}
+}
+
+// Stops recording changes and discards the history
+func (t *ParamTableOf[E]) DisableHistory() {
+	t.history = nil
+}
+
+// Discards every recorded entry, keeping history enabled
+func (t *ParamTableOf[E]) ClearHistory() {
+	if t.history != nil {
+		clear(t.history.entries)
+		t.history.entries = t.history.entries[:0]
+		t.history.pos = 0
+	}
+}
+
+// Ends coalescing: the next change starts a new entry even within the coalesce window, for
+// example when the user releases a slider
+func (t *ParamTableOf[E]) SealHistory() {
+	if t.history != nil {
+		t.history.sealed = true
+	}
+}
+
+// Whether Undo() has an entry to undo
+func (t *ParamTableOf[E]) CanUndo() bool {
+	return t.history != nil && t.history.pos > 0
+}
+
+// Whether Redo() has an entry to redo
+func (t *ParamTableOf[E]) CanRedo() bool {
+	return t.history != nil && t.history.pos < len(t.history.entries)
+}
+
+// Sets every root changed by the last recorded entry back to the value it had before, then
+// recalculates the values derived from them. Roots that have since become derived values
+// (or were un-initialized) are skipped. Returns false if there was nothing to undo.
+// Panics if a batch is open
+func (t *ParamTableOf[E]) Undo() bool {
+	t.checkNoBatch("Undo")
+	if !t.CanUndo() {
+		return false
+	}
+	t.history.pos -= 1
+	changes := t.history.entries[t.history.pos].changes
+	t.applyHistory(len(changes), func(i int) (*historyChange, *savedValue) {
+		change := &changes[len(changes)-1-i]
+		return change, &change.old
+	})
+	return true
+}
+
+// Sets every root changed by the last undone entry to the value it was changed to, then
+// recalculates the values derived from them. Returns false if there was nothing to redo.
+// Panics if a batch is open
+func (t *ParamTableOf[E]) Redo() bool {
+	t.checkNoBatch("Redo")
+	if !t.CanRedo() {
+		return false
+	}
+	changes := t.history.entries[t.history.pos].changes
+	t.history.pos += 1
+	t.applyHistory(len(changes), func(i int) (*historyChange, *savedValue) {
+		return &changes[i], &changes[i].new
+	})
+	return true
+}
+
+// Writes count recorded values as one batch, so that every affected calc runs exactly once
+func (t *ParamTableOf[E]) applyHistory(count int, value func(i int) (*historyChange, *savedValue)) {
+	t.history.applying = true
+	defer func() { t.history.applying = false }()
+	t.BeginBatch()
+	for i := 0; i < count; i += 1 {
+		change, val := value(i)
+		if !getFlag(change.idx, t.flags).IsInit() || t.isDerived(E(change.idx)) {
+			continue
+		}
+		t.stageRoot(change.idx, int(change.typeIdx))
+		t.loadValue(change.idx, int(change.typeIdx), val)
+	}
+	t.CommitBatch()
+	t.history.sealed = true
+}
+
+func (t *ParamTableOf[E]) checkNoBatch(funcName string) {
+	if err := t.noBatchErr(funcName); err != nil {
+		t.fail(err)
+	}
+}
+
+func (t *ParamTableOf[E]) noBatchErr(funcName string) error {
+	if len(t.batch.starts) > 0 {
+		return newParamError(ErrBatchOpen, ^E(0), "%s(): a batch is open, call CommitBatch() or Rollback() first", funcName)
+	}
+	return nil
+}
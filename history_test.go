@@ -0,0 +1,242 @@
+package go_param_table
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistoryUndoRedo(t *testing.T) {
+	t.Run("narrow", testHistoryUndoRedo[uint16])
+	t.Run("wide", testHistoryUndoRedo[uint32])
+}
+
+func testHistoryUndoRedo[E Index](t *testing.T) {
+	// 0 -> 1, 2 -> 3 = 1 + 2
+	table, evals := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 1, false)
+	derive_F64(table, 1, _TEST_CALC_ADD_ONE, 0)
+	table.InitRoot_F64(2, 10, false)
+	derive_F64(table, 3, _TEST_CALC_SUM, 1, 2)
+	var expectVals = func(name string, exp ...float64) {
+		t.Helper()
+		for i, val := range exp {
+			if got := table.Get_F64(PIdx_F64(i)); got != val {
+				t.Errorf("%s: idx %d:\n\tEXP: %f\n\tGOT: %f", name, i, val, got)
+			}
+		}
+	}
+	if table.Undo() || table.CanUndo() {
+		t.Errorf("undo without history")
+	}
+	table.EnableHistory()
+
+	table.SetRoot_F64(0, 2)
+	table.SetRoot_F64(2, 20)
+	table.SetRoot_F64(2, 20) // unchanged, not recorded
+	table.BeginBatch()
+	table.SetRoot_F64(0, 5)
+	table.SetRoot_F64(2, 50)
+	table.SetRoot_F64(0, 6)
+	table.CommitBatch()
+	table.BeginBatch()
+	table.SetRoot_F64(0, 100)
+	table.Rollback()
+	expectVals("edited", 6, 7, 50, 57)
+
+	*evals = 0
+	if !table.Undo() {
+		t.Fatalf("undo of batch returned false")
+	}
+	expectVals("undo batch", 2, 3, 20, 23)
+	if *evals != 2 {
+		t.Errorf("undo of batch evaluated %d calcs for 2 derived values", *evals)
+	}
+	table.Undo()
+	expectVals("undo second", 2, 3, 10, 13)
+	table.Undo()
+	expectVals("undo first", 1, 2, 10, 12)
+	if table.Undo() || table.CanUndo() || !table.CanRedo() {
+		t.Errorf("undo past the first entry")
+	}
+
+	table.Redo()
+	table.Redo()
+	expectVals("redo twice", 2, 3, 20, 23)
+	table.Redo()
+	expectVals("redo batch", 6, 7, 50, 57)
+	if table.Redo() {
+		t.Errorf("redo past the last entry")
+	}
+
+	// a new edit discards the entries that could be redone
+	table.Undo()
+	table.SetRoot_F64(0, 9)
+	if table.CanRedo() {
+		t.Errorf("redo still possible after a new edit")
+	}
+	table.Undo()
+	expectVals("undo after new edit", 2, 3, 20, 23)
+
+	table.BeginBatch()
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrBatchOpen) {
+				t.Errorf("undo inside a batch:\n\tEXP: %v\n\tGOT: %v", ErrBatchOpen, err)
+			}
+		}()
+		table.Undo()
+	}()
+	table.Rollback()
+
+	// roots that became derived are left alone
+	table.SetRoot_F64(2, 1)
+	table.Rewire(2, _TEST_CALC_DOUBLE, []E{0}, []E{2})
+	table.Undo()
+	expectVals("undo of rewired root", 2, 3, 4, 7)
+
+	table.ClearHistory()
+	if table.CanUndo() || table.CanRedo() {
+		t.Errorf("history not cleared")
+	}
+	table.DisableHistory()
+	table.SetRoot_F64(0, 3)
+	if table.CanUndo() {
+		t.Errorf("change recorded with history disabled")
+	}
+}
+
+func TestHistoryDepthAndCoalescing(t *testing.T) {
+	t.Run("narrow", testHistoryDepthAndCoalescing[uint16])
+	t.Run("wide", testHistoryDepthAndCoalescing[uint32])
+}
+
+func testHistoryDepthAndCoalescing[E Index](t *testing.T) {
+	table, _ := newF64TestTableOf[E](2, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 0, false)
+	table.InitRoot_F64(1, 0, false)
+	table.EnableHistory(WithHistoryDepth(3))
+	for i := 1; i <= 5; i += 1 {
+		table.SetRoot_F64(0, float64(i))
+	}
+	undone := 0
+	for table.Undo() {
+		undone += 1
+	}
+	if got := table.Get_F64(0); undone != 3 || got != 2 {
+		t.Errorf("depth 3:\n\tEXP: 3 entries undone to 2\n\tGOT: %d entries undone to %f", undone, got)
+	}
+
+	// every step of a drag is undone at once, until the history is sealed
+	table.ClearHistory()
+	table.EnableHistory(WithCoalesceWindow(time.Hour))
+	for i := 10; i <= 15; i += 1 {
+		table.SetRoot_F64(0, float64(i))
+	}
+	table.SealHistory()
+	table.SetRoot_F64(0, 20)
+	table.SetRoot_F64(1, 1)
+	table.SetRoot_F64(1, 2)
+	table.Undo()
+	if got := table.Get_F64(1); got != 0 {
+		t.Errorf("coalesced edits of another root:\n\tEXP: 0\n\tGOT: %f", got)
+	}
+	table.Undo()
+	if got := table.Get_F64(0); got != 15 {
+		t.Errorf("sealed history:\n\tEXP: 15\n\tGOT: %f", got)
+	}
+	table.Undo()
+	if got := table.Get_F64(0); got != 2 {
+		t.Errorf("coalesced drag:\n\tEXP: 2\n\tGOT: %f", got)
+	}
+
+	// edits further apart than the window are separate entries
+	table.ClearHistory()
+	table.EnableHistory(WithCoalesceWindow(time.Millisecond))
+	table.SetRoot_F64(0, 30)
+	time.Sleep(5 * time.Millisecond)
+	table.SetRoot_F64(0, 31)
+	table.Undo()
+	if got := table.Get_F64(0); got != 30 {
+		t.Errorf("edits outside the window:\n\tEXP: 30\n\tGOT: %f", got)
+	}
+
+	clone := table.Clone()
+	clone.Undo()
+	if table.Get_F64(0) != 30 || clone.Get_F64(0) != 2 {
+		t.Errorf("cloned history is not independent: %f, %f", table.Get_F64(0), clone.Get_F64(0))
+	}
+}
+
+func TestHistoryOpaqueValues(t *testing.T) {
+	t.Run("narrow", testHistoryOpaqueValues[uint16])
+	t.Run("wide", testHistoryOpaqueValues[uint32])
+}
+
+func testHistoryOpaqueValues[E Index](t *testing.T) {
+	b := newTestBuilderOf[E](WithDebug(true), WithVerbosity(VerbositySilent))
+	label := b.Str("label")
+	tags := DeclareVal[[]string](b, "tags")
+	table := b.Build()
+	table.InitRoot_Str(*label, "a", false)
+	InitRootVal(&table, *tags, []string{"x"}, false, nil)
+	table.EnableHistory()
+	table.SetRoot_Str(*label, "b")
+	SetVal(&table, *tags, []string{"y", "z"})
+	table.Undo()
+	table.Undo()
+	if got := GetVal(&table, *tags); table.Get_Str(*label) != "a" || len(got) != 1 || got[0] != "x" {
+		t.Errorf("undo:\n\tEXP: a [x]\n\tGOT: %s %v", table.Get_Str(*label), got)
+	}
+	table.Redo()
+	table.Redo()
+	if got := GetVal(&table, *tags); table.Get_Str(*label) != "b" || len(got) != 2 {
+		t.Errorf("redo:\n\tEXP: b [y z]\n\tGOT: %s %v", table.Get_Str(*label), got)
+	}
+}
+
+func TestHistoryInitRoot(t *testing.T) {
+	t.Run("narrow", testHistoryInitRoot[uint16])
+	t.Run("wide", testHistoryInitRoot[uint32])
+}
+
+func testHistoryInitRoot[E Index](t *testing.T) {
+	// 0 -> 1
+	table, _ := newF64TestTableOf[E](3, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 1, false)
+	derive_F64(table, 1, _TEST_CALC_ADD_ONE, 0)
+	table.EnableHistory()
+
+	// a batch could not roll the initialization back
+	table.BeginBatch()
+	if err := table.TryInitRoot_F64(2, 5, false); !errors.Is(err, ErrBatchOpen) || table.IsInit(2) {
+		t.Errorf("init in batch:\n\tEXP: %v, not initialized\n\tGOT: %v, initialized %t", ErrBatchOpen, err, table.IsInit(2))
+	}
+	func() {
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, ErrBatchOpen) {
+				t.Errorf("init in batch:\n\tEXP: %v\n\tGOT: %v", ErrBatchOpen, err)
+			}
+		}()
+		table.InitRoot_F64(0, 9, false)
+	}()
+	table.Rollback()
+	if table.Get_F64(0) != 1 {
+		t.Errorf("init in batch changed the root: %f", table.Get_F64(0))
+	}
+
+	// the first initialization is not an edit, re-initializing is
+	table.InitRoot_F64(2, 5, false)
+	if table.CanUndo() {
+		t.Errorf("first initialization recorded by the undo history")
+	}
+	table.InitRoot_F64(0, 3, false)
+	if table.Get_F64(1) != 4 || !table.CanUndo() {
+		t.Errorf("re-initialization:\n\tEXP: 4, undoable\n\tGOT: %f, undoable %t", table.Get_F64(1), table.CanUndo())
+	}
+	table.Undo()
+	if table.Get_F64(0) != 1 || table.Get_F64(1) != 2 {
+		t.Errorf("undo re-initialization:\n\tEXP: 1 -> 2\n\tGOT: %f -> %f", table.Get_F64(0), table.Get_F64(1))
+	}
+}
@@ -1,28 +1,48 @@
 package go_param_table
 
 import (
-	"fmt"
 	"slices"
+	"unsafe"
 )
 
+// Each initialized value owns one segment of hookupData:
+//
+//	[calc, paramsLen, childLen, childCap, inputs..., outputs..., children..., unused child slots...]
+//
+// Children are appended into the unused slots. When none are left the segment is moved to the
+// end of hookupData with twice the child capacity and the old copy becomes garbage, so adding a
+// child is O(1) amortized and never moves any other segment. Garbage is removed by compaction
 const (
 	_HOOK_OFF_CALC    uint32 = 0
 	_HOOK_OFF_PLEN    uint32 = 1
 	_HOOK_OFF_CLEN    uint32 = 2
-	_HOOK_OFF_INSTART uint32 = 3
+	_HOOK_OFF_CCAP    uint32 = 3
+	_HOOK_OFF_INSTART uint32 = 4
 )
 
-type paramsLen uint16
+// The input and output counts of a segment share its paramsLen entry, each taking half of its
+// bits: 8 bits each in a ParamTable, 16 bits each in a WideParamTable
+func paramsLenShift[E Index]() uint32 {
+	return uint32(unsafe.Sizeof(E(0))) * 4
+}
+
+// The maximum number of inputs (or outputs) of one derived value
+func maxParamsLen[E Index]() int {
+	return 1<<paramsLenShift[E]() - 1
+}
 
-func newParamsLen(inLen uint32, outLen uint32) paramsLen {
-	return (paramsLen(inLen) << 8) | paramsLen(outLen)
+// The maximum number of children of one value, the largest count a segment header can hold
+func maxChildren[E Index]() int {
+	return int(^E(0))
 }
 
-func (l paramsLen) inLen() uint32 {
-	return uint32(l >> 8)
+func newParamsLen[E Index](inLen uint32, outLen uint32) E {
+	return E(inLen<<paramsLenShift[E]() | outLen)
 }
-func (l paramsLen) outLen() uint32 {
-	return uint32(l & 0b11111111)
+
+func splitParamsLen[E Index](l E) (inLen uint32, outLen uint32) {
+	shift := paramsLenShift[E]()
+	return uint32(l) >> shift, uint32(l) & (1<<shift - 1)
 }
 
 type hookup uint32
@@ -31,21 +51,21 @@ func (h hookup) isInit() bool {
 	return h != 0
 }
 
-func (t *ParamTable) isDerived(idx uint16) bool {
+func (t *ParamTableOf[E]) isDerived(idx E) bool {
 	h := t.hookups[idx]
 	if !h.isInit() {
 		return false
 	}
 	return t.hookupData[uint32(h)+_HOOK_OFF_PLEN] != 0
 }
-func (t *ParamTable) isRoot(idx uint16) bool {
+func (t *ParamTableOf[E]) isRoot(idx E) bool {
 	h := t.hookups[idx]
 	if !h.isInit() {
 		return false
 	}
 	return t.hookupData[uint32(h)+_HOOK_OFF_PLEN] == 0
 }
-func (t *ParamTable) hasChildren(idx uint16) bool {
+func (t *ParamTableOf[E]) hasChildren(idx E) bool {
 	h := t.hookups[idx]
 	if !h.isInit() {
 		return false
@@ -53,28 +73,31 @@ func (t *ParamTable) hasChildren(idx uint16) bool {
 	return t.hookupData[uint32(h)+_HOOK_OFF_CLEN] != 0
 }
 
-func (t *ParamTable) trigger(idx uint16, prevIdxs []uint16) (updatedPrevIdxs []uint16) {
+func (t *ParamTableOf[E]) trigger(idx E) {
+	t.triggerInto(idx, nil)
+}
+
+// Runs the calc of idx. If changed is not nil, the outputs that changed are appended to it
+// instead of being marked changed, so that calcs can run concurrently
+func (t *ParamTableOf[E]) triggerInto(idx E, changed *[]E) {
 	h := t.hookups[idx]
 	i := uint32(h)
 	calcIdx := PIdx_Calc(t.hookupData[i])
-	paramsLen := paramsLen(t.hookupData[i+_HOOK_OFF_PLEN])
-	inLen := paramsLen.inLen()
-	outLen := paramsLen.outLen()
+	inLen, outLen := splitParamsLen(t.hookupData[i+_HOOK_OFF_PLEN])
 	inout := i + _HOOK_OFF_INSTART
 	insEnd := inout + inLen
 	ins := t.hookupData[inout:insEnd]
 	outs := t.hookupData[insEnd : insEnd+outLen]
-	iface := CalcInterface{
-		table:    t,
-		inputs:   ins,
-		outputs:  outs,
-		prevIdxs: prevIdxs,
+	iface := CalcInterfaceOf[E]{
+		table:   t,
+		inputs:  ins,
+		outputs: outs,
+		changed: changed,
 	}
 	t.calcs[calcIdx](&iface)
-	return iface.prevIdxs
 }
 
-func (t *ParamTable) getCalcFromValIdx(idx uint16) (calc ParamCalc) {
+func (t *ParamTableOf[E]) getCalcFromValIdx(idx E) (calc ParamCalcOf[E]) {
 	h := t.hookups[idx]
 	if !h.isInit() {
 		return nil
@@ -83,109 +106,269 @@ func (t *ParamTable) getCalcFromValIdx(idx uint16) (calc ParamCalc) {
 	return t.calcs[calcIdx]
 }
 
-func (t *ParamTable) getParents(idx uint16) (parents []uint16) {
+func (t *ParamTableOf[E]) getParents(idx E) (parents []E) {
 	start, end := t.getParentsLimits(idx)
 	return t.hookupData[start:end]
 }
-func (t *ParamTable) getParentsLimits(idx uint16) (start, end uint32) {
+func (t *ParamTableOf[E]) getParentsLimits(idx E) (start, end uint32) {
 	h := t.hookups[idx]
 	i := uint32(h)
 	if !h.isInit() {
 		return 0, 0
 	}
-	paramsLen := paramsLen(t.hookupData[i+_HOOK_OFF_PLEN])
-	inLen := paramsLen.inLen()
+	inLen, _ := splitParamsLen(t.hookupData[i+_HOOK_OFF_PLEN])
 	start = i + _HOOK_OFF_INSTART
 	end = start + inLen
 	return
 }
 
-func (t *ParamTable) getSiblings(idx uint16) (siblings []uint16) {
+func (t *ParamTableOf[E]) getSiblings(idx E) (siblings []E) {
 	start, end := t.getSiblingsLimits(idx)
 	return t.hookupData[start:end]
 }
-func (t *ParamTable) getSiblingsLimits(idx uint16) (start, end uint32) {
+func (t *ParamTableOf[E]) getSiblingsLimits(idx E) (start, end uint32) {
 	h := t.hookups[idx]
 	i := uint32(h)
 	if !h.isInit() {
 		return 0, 0
 	}
-	paramsLen := paramsLen(t.hookupData[i+_HOOK_OFF_PLEN])
-	inLen := paramsLen.inLen()
-	outLen := paramsLen.outLen()
+	inLen, outLen := splitParamsLen(t.hookupData[i+_HOOK_OFF_PLEN])
 	start = i + _HOOK_OFF_INSTART + inLen
 	end = start + outLen
 	return
 }
-func (t *ParamTable) getChildren(idx uint16) (children []uint16) {
+func (t *ParamTableOf[E]) childCount(idx E) int {
+	start, end := t.getChildrenLimits(idx)
+	return int(end - start)
+}
+func (t *ParamTableOf[E]) getChildren(idx E) (children []E) {
 	start, end := t.getChildrenLimits(idx)
 	return t.hookupData[start:end]
 }
-func (t *ParamTable) getChildrenLimits(idx uint16) (start, end uint32) {
+func (t *ParamTableOf[E]) getChildrenLimits(idx E) (start, end uint32) {
 	h := t.hookups[idx]
 	if !h.isInit() {
 		return 0, 0
 	}
 	i := uint32(h)
-	paramsLen := paramsLen(t.hookupData[i+_HOOK_OFF_PLEN])
-	inLen := paramsLen.inLen()
-	outLen := paramsLen.outLen()
+	inLen, outLen := splitParamsLen(t.hookupData[i+_HOOK_OFF_PLEN])
 	childLen := uint32(t.hookupData[i+_HOOK_OFF_CLEN])
 	start = i + _HOOK_OFF_INSTART + inLen + outLen
 	end = start + childLen
 	return
 }
 
-func (t *ParamTable) addChild(idx uint16, childIdx uint16) {
+func (t *ParamTableOf[E]) addChild(idx E, childIdx E) {
 	h := t.hookups[idx]
-	i := uint32(h)
 	if !h.isInit() {
 		t.initRootHookupWithChild(idx, childIdx)
 		return
 	}
 	start, end := t.getChildrenLimits(idx)
-	for start < end {
-		if t.hookupData[start] == childIdx {
-			if EnableDebug {
-				fmt.Fprintf(DebugWriter, "warn: go_param_table: hookup.addChild(): child idx %d was already inside hookup idx %d (owned by value idx %d)", childIdx, h, idx)
-			}
+	for i := start; i < end; i += 1 {
+		if t.hookupData[i] == childIdx {
+			t.warnf("hookup.addChild(): child idx %s was already inside hookup idx %d (owned by value idx %s)", t.paramLabel(uint32(childIdx)), h, t.paramLabel(uint32(idx)))
 			return
 		}
-		start += 1
 	}
-	for i, hh := range t.hookups {
-		if uint32(hh) >= end {
-			t.hookups[i] += 1
-		}
+	if end-start >= uint32(maxChildren[E]()) {
+		t.fail(newParamError(ErrTooManyChildren, idx, "hookup.addChild(): index %s already has the maximum of %d children", t.paramLabel(uint32(idx)), maxChildren[E]()))
+	}
+	if end-start == uint32(t.hookupData[uint32(h)+_HOOK_OFF_CCAP]) {
+		t.growChildren(idx)
+		h = t.hookups[idx]
+		_, end = t.getChildrenLimits(idx)
 	}
-	t.hookupData = slices.Insert(t.hookupData, int(end), childIdx)
-	t.hookupData[i+_HOOK_OFF_CLEN] += 1
 	t.hookupData[end] = childIdx
+	t.hookupData[uint32(h)+_HOOK_OFF_CLEN] += 1
+}
+
+// Moves the segment of idx to the end of hookupData with twice the child capacity (at most
+// maxChildren(), which addChild() never exceeds)
+func (t *ParamTableOf[E]) growChildren(idx E) {
+	h := uint32(t.hookups[idx])
+	_, end := t.getChildrenLimits(idx)
+	childCap := uint32(t.hookupData[h+_HOOK_OFF_CCAP])
+	newCap := min(max(2, childCap*2), uint32(^E(0)))
+	segLen := t.segmentLen(idx)
+	newStart := uint32(len(t.hookupData))
+	t.hookupData = slices.Grow(t.hookupData, int(end-h+newCap-childCap))
+	t.hookupData = append(t.hookupData, t.hookupData[h:end]...)
+	t.hookupData = append(t.hookupData, make([]E, newCap-(end-h-_HOOK_OFF_INSTART-t.paramsLen(idx)))...)
+	t.hookupData[newStart+_HOOK_OFF_CCAP] = E(newCap)
+	t.hookups[idx] = hookup(newStart)
+	t.addGarbage(segLen)
+}
+
+func (t *ParamTableOf[E]) paramsLen(idx E) uint32 {
+	inLen, outLen := splitParamsLen(t.hookupData[uint32(t.hookups[idx])+_HOOK_OFF_PLEN])
+	return inLen + outLen
 }
 
-func (t *ParamTable) removeChild(idx uint16, childIdx uint16) {
+func (t *ParamTableOf[E]) removeChild(idx E, childIdx E) {
 	h := t.hookups[idx]
-	i := uint32(h)
-	if EnableDebug {
-		if !h.isInit() {
-			fmt.Fprintf(DebugWriter, "warn: go_param_table: hookup.removeChild(): idx %d never had its hookup initialized", idx)
+	if !h.isInit() {
+		t.warnf("hookup.removeChild(): idx %s never had its hookup initialized", t.paramLabel(uint32(idx)))
+		return
+	}
+	start, end := t.getChildrenLimits(idx)
+	for i := start; i < end; i += 1 {
+		if t.hookupData[i] == childIdx {
+			copy(t.hookupData[i:end], t.hookupData[i+1:end])
+			t.hookupData[end-1] = 0
+			t.hookupData[uint32(h)+_HOOK_OFF_CLEN] -= 1
 			return
 		}
 	}
-	start, end := t.getChildrenLimits(idx)
-	for start < end {
-		if t.hookupData[start] == childIdx {
-			t.hookupData = slices.Delete(t.hookupData, int(start), int(start+1))
-			for i, hh := range t.hookups {
-				if uint32(hh) >= end {
-					t.hookups[i] -= 1
-				}
+	t.warnf("hookup.removeChild(): child idx %s was not inside hookup idx %d (owned by value idx %s)", t.paramLabel(uint32(childIdx)), h, t.paramLabel(uint32(idx)))
+}
+
+// The length of the segment of idx, including its unused child slots
+func (t *ParamTableOf[E]) segmentLen(idx E) uint32 {
+	h := t.hookups[idx]
+	if !h.isInit() {
+		return 0
+	}
+	return _HOOK_OFF_INSTART + t.paramsLen(idx) + uint32(t.hookupData[uint32(h)+_HOOK_OFF_CCAP])
+}
+
+// Builds a segment with no unused child slots
+func newSegment[E Index](calcIdx PIdx_Calc, inputs []E, outputs []E, children []E) []E {
+	seg := make([]E, 0, _HOOK_OFF_INSTART+uint32(len(inputs)+len(outputs)+len(children)))
+	paramsLen := newParamsLen[E](uint32(len(inputs)), uint32(len(outputs)))
+	seg = append(seg, E(calcIdx), paramsLen, E(len(children)), E(len(children)))
+	seg = append(seg, inputs...)
+	seg = append(seg, outputs...)
+	return append(seg, children...)
+}
+
+// Replaces the segment of idx with seg (built by newSegment()). The old segment is reused if
+// seg fits inside it, otherwise it becomes garbage and seg is appended to the end of hookupData
+func (t *ParamTableOf[E]) replaceSegment(idx E, seg []E) {
+	if h := t.hookups[idx]; h.isInit() && t.segmentLen(idx) >= uint32(len(seg)) {
+		oldLen := t.segmentLen(idx)
+		copy(t.hookupData[h:], seg)
+		clear(t.hookupData[uint32(h)+uint32(len(seg)) : uint32(h)+oldLen])
+		t.hookupData[uint32(h)+_HOOK_OFF_CCAP] += E(oldLen - uint32(len(seg)))
+		return
+	}
+	t.deleteSegment(idx)
+	t.hookups[idx] = hookup(len(t.hookupData))
+	t.hookupData = append(t.hookupData, seg...)
+}
+
+// Detaches the segment of idx, leaving it as garbage
+func (t *ParamTableOf[E]) deleteSegment(idx E) {
+	if !t.hookups[idx].isInit() {
+		return
+	}
+	segLen := t.segmentLen(idx)
+	t.hookups[idx] = 0
+	t.addGarbage(segLen)
+}
+
+func (t *ParamTableOf[E]) addGarbage(segLen uint32) {
+	t.hookupGarbage += segLen
+	// only once garbage makes up half of hookupData, so compaction stays O(1) amortized
+	if t.hookupGarbage > 64 && t.hookupGarbage*2 > uint32(len(t.hookupData)) {
+		t.compactHookups(false)
+	}
+}
+
+// Rewrites hookupData without any garbage. If tight is true, all unused child slots are
+// removed as well and the slice is allocated at its exact size
+func (t *ParamTableOf[E]) compactHookups(tight bool) {
+	size := 1
+	for i, h := range t.hookups {
+		if h.isInit() {
+			if tight {
+				_, end := t.getChildrenLimits(E(i))
+				size += int(end - uint32(h))
+			} else {
+				size += int(t.segmentLen(E(i)))
 			}
-			t.hookupData[i+_HOOK_OFF_CLEN] -= 1
-			return
 		}
 	}
-	if EnableDebug {
-		fmt.Fprintf(DebugWriter, "warn: go_param_table: hookup.removeChild(): child idx %d was not inside hookup idx %d (owned by value idx %d)", childIdx, h, idx)
+	data := make([]E, 1, size)
+	for i, h := range t.hookups {
+		if !h.isInit() {
+			continue
+		}
+		idx := E(i)
+		segStart := uint32(len(data))
+		_, end := t.getChildrenLimits(idx)
+		data = append(data, t.hookupData[h:end]...)
+		if tight {
+			data[segStart+_HOOK_OFF_CCAP] = data[segStart+_HOOK_OFF_CLEN]
+		} else {
+			data = append(data, make([]E, uint32(h)+t.segmentLen(idx)-end)...)
+		}
+		t.hookups[idx] = hookup(segStart)
+	}
+	t.hookupData = data
+	t.hookupGarbage = 0
+}
+
+// Removes all unused space from the dependency storage. Adding children leaves spare room
+// behind for later additions, so a table that was just initialized uses up to twice the space
+// it needs. Call it once the InitDerived_*() calls are done to bring the table to its minimum
+// TotalMemoryFootprint(), and again after restructuring it later on. O(N), it copies the whole
+// storage; the next child added to a value afterwards has to move its segment
+func (t *ParamTableOf[E]) Compact() {
+	t.compactHookups(true)
+}
+
+// Removes idx from the child list of each of its parents
+func (t *ParamTableOf[E]) detachFromParents(idx E) {
+	parents := slices.Clone(t.getParents(idx))
+	slices.Sort(parents)
+	for _, parent := range slices.Compact(parents) {
+		t.removeChild(parent, idx)
+	}
+}
+
+// Returns every value idx is (transitively) calculated from, in breadth-first order
+func (t *ParamTableOf[E]) ancestors(idx E) []E {
+	seen := make([]bool, len(t.hookups))
+	seen[idx] = true
+	var found []E
+	for next := []E{idx}; len(next) > 0; {
+		n := next[0]
+		next = next[1:]
+		for _, parent := range t.getParents(n) {
+			if !seen[parent] {
+				seen[parent] = true
+				found = append(found, parent)
+				next = append(next, parent)
+			}
+		}
+	}
+	return found
+}
+
+// Returns every value that may be recalculated when idx changes (the outputs of every calc
+// downstream of idx), in breadth-first order
+func (t *ParamTableOf[E]) descendants(idx E) []E {
+	seen := make([]bool, len(t.hookups))
+	seen[idx] = true
+	var found []E
+	var visit = func(n E, next []E) []E {
+		if !seen[n] {
+			seen[n] = true
+			found = append(found, n)
+			next = append(next, n)
+		}
+		return next
+	}
+	for next := []E{idx}; len(next) > 0; {
+		n := next[0]
+		next = next[1:]
+		for _, child := range t.getChildren(n) {
+			next = visit(child, next)
+			for _, sib := range t.getSiblings(child) {
+				next = visit(sib, next)
+			}
+		}
 	}
+	return found
 }
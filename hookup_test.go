@@ -0,0 +1,112 @@
+package go_param_table
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Builds a table where every derived value i is calculated from i-1 and i/2, so early values
+// collect many children over the course of construction
+func buildConstructionTable[E Index](count E) *ParamTableOf[E] {
+	table, _ := newF64TestTableOf[E](uint32(count), WithDebug(false))
+	table.InitRoot_F64(0, 1, false)
+	for i := E(1); i < count; i += 1 {
+		derive_F64(table, i, _TEST_CALC_SUM, i-1, i/2)
+	}
+	return table
+}
+
+func TestHookupGrowthAndCompaction(t *testing.T) {
+	t.Run("narrow", testHookupGrowthAndCompaction[uint16])
+	t.Run("wide", testHookupGrowthAndCompaction[uint32])
+}
+
+func testHookupGrowthAndCompaction[E Index](t *testing.T) {
+	const count = 3000
+	table := buildConstructionTable[E](count)
+	table.Configure(WithDebug(true))
+	var expectVals = func(name string, root float64) {
+		t.Helper()
+		checkHookupConsistency(t, table)
+		exp := make([]float64, count)
+		exp[0] = root
+		for i := 1; i < count; i += 1 {
+			exp[i] = exp[i-1] + exp[i/2]
+		}
+		for i := E(0); i < count; i += 1 {
+			if got := table.Get_F64(PIdx_F64(i)); got != exp[i] {
+				t.Fatalf("%s: idx %d:\n\tEXP: %f\n\tGOT: %f", name, i, exp[i], got)
+			}
+		}
+	}
+	expectVals("after construction", 1)
+	if len(table.Children(1)) != 2 || len(table.Children(2)) != 3 {
+		t.Errorf("children error:\n\tEXP: 2 3\n\tGOT: %d %d", len(table.Children(1)), len(table.Children(2)))
+	}
+
+	before := table.TotalMemoryFootprint()
+	table.Compact()
+	if after := table.TotalMemoryFootprint(); after >= before {
+		t.Errorf("Compact() did not reduce the footprint: %d -> %d", before, after)
+	}
+	if table.hookupGarbage != 0 || len(table.hookupData) != cap(table.hookupData) {
+		t.Errorf("Compact() left %d garbage entries and %d unused capacity", table.hookupGarbage, cap(table.hookupData)-len(table.hookupData))
+	}
+	table.SetRoot_F64(0, 0.5)
+	expectVals("after compaction", 0.5)
+
+	// rewiring back and forth produces garbage, which must be collected along the way
+	compactLen := len(table.hookupData)
+	for i := 0; i < 5000; i += 1 {
+		table.Rewire(count-1, _TEST_CALC_SUM, []E{1, 2, 3}, []E{count - 1})
+		table.Rewire(count-1, _TEST_CALC_SUM, []E{count - 2, (count - 1) / 2}, []E{count - 1})
+	}
+	if len(table.hookupData) > (compactLen+64)*2 {
+		t.Errorf("garbage was never collected: %d entries after compaction, %d (%d garbage) after rewiring", compactLen, len(table.hookupData), table.hookupGarbage)
+	}
+	table.SetRoot_F64(0, 2)
+	expectVals("after rewiring", 2)
+}
+
+func TestHookupCompactAfterConstruction(t *testing.T) {
+	t.Run("narrow", testHookupCompactAfterConstruction[uint16])
+	t.Run("wide", testHookupCompactAfterConstruction[uint32])
+}
+
+func testHookupCompactAfterConstruction[E Index](t *testing.T) {
+	table := buildConstructionTable[E](500)
+	constructed := cap(table.hookupData)
+	// updates never restructure the storage by themselves
+	table.SetRoot_F64(0, 2)
+	if cap(table.hookupData) != constructed {
+		t.Errorf("root update changed the dependency storage: %d -> %d entries", constructed, cap(table.hookupData))
+	}
+	before := table.TotalMemoryFootprint()
+	table.Compact()
+	if after := table.TotalMemoryFootprint(); after >= before || cap(table.hookupData) != len(table.hookupData) {
+		t.Errorf("Compact() after construction:\n\tEXP: less than %d bytes, no unused capacity\n\tGOT: %d bytes, %d unused entries", before, after, cap(table.hookupData)-len(table.hookupData))
+	}
+	checkHookupConsistency(t, table)
+	// adding a child after compaction regrows the segment
+	table.Rewire(499, _TEST_CALC_SUM, []E{0, 1, 2}, []E{499})
+	checkHookupConsistency(t, table)
+	table.SetRoot_F64(0, 3)
+	if got := table.Get_F64(1); got != 6 {
+		t.Errorf("value after compaction:\n\tEXP: 6\n\tGOT: %f", got)
+	}
+}
+
+func BenchmarkTableConstruction(b *testing.B) {
+	b.Run("narrow", benchmarkTableConstruction[uint16])
+	b.Run("wide", benchmarkTableConstruction[uint32])
+}
+
+func benchmarkTableConstruction[E Index](b *testing.B) {
+	for _, count := range []E{1000, 10000, 60000} {
+		b.Run(fmt.Sprintf("params_%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i += 1 {
+				buildConstructionTable[E](count)
+			}
+		})
+	}
+}
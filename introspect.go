@@ -0,0 +1,169 @@
+package go_param_table
+
+import (
+	"slices"
+	"strconv"
+)
+
+// The value type of a parameter, as returned by TypeOf()
+type ParamType uint8
+
+const (
+	TypeU64  ParamType = typeU64
+	TypeI64  ParamType = typeI64
+	TypeF64  ParamType = typeF64
+	TypePtr  ParamType = typePtr
+	TypeStr  ParamType = typeStr
+	TypeVal  ParamType = typeVal
+	TypeU32  ParamType = typeU32
+	TypeI32  ParamType = typeI32
+	TypeF32  ParamType = typeF32
+	TypeU16  ParamType = typeU16
+	TypeI16  ParamType = typeI16
+	TypeU8   ParamType = typeU8
+	TypeI8   ParamType = typeI8
+	TypeBool ParamType = typeBool
+)
+
+func (p ParamType) String() string {
+	if int(p) >= typeCount {
+		return "ParamType(" + strconv.FormatUint(uint64(p), 10) + ")"
+	}
+	return typeNames[p]
+}
+
+// All functions below are read-only: returned slices are copies and can be modified freely.
+// They panic on an out of range index when debug checks are enabled
+
+func (t *ParamTableOf[E]) checkBounds(idx E) {
+	if t.debug.enabled && int(idx) >= len(t.hookups) {
+		t.fail(newParamError(ErrIndexOutOfRange, idx, "index %d is outside bounds of parameter list (len %d)", idx, len(t.hookups)))
+	}
+}
+
+// Returns the number of parameters in the table
+func (t *ParamTableOf[E]) Len() int {
+	return len(t.hookups)
+}
+
+// Returns whether the parameter was initialized with InitRoot_*() or InitDerived_*()
+func (t *ParamTableOf[E]) IsInit(idx E) bool {
+	t.checkBounds(idx)
+	return getFlag(uint32(idx), t.flags).IsInit()
+}
+
+// Returns whether the parameter is an initialized root value (set directly, not calculated)
+func (t *ParamTableOf[E]) IsRoot(idx E) bool {
+	return t.IsInit(idx) && !t.isDerived(idx)
+}
+
+// Returns whether the parameter is a derived value (calculated from its parents)
+func (t *ParamTableOf[E]) IsDerived(idx E) bool {
+	t.checkBounds(idx)
+	return t.isDerived(idx)
+}
+
+// Returns whether any derived value is calculated from the parameter
+func (t *ParamTableOf[E]) HasChildren(idx E) bool {
+	t.checkBounds(idx)
+	return t.hasChildren(idx)
+}
+
+// Returns the inputs of the calc of a derived value, in calc input order
+func (t *ParamTableOf[E]) Parents(idx E) []E {
+	t.checkBounds(idx)
+	return slices.Clone(t.getParents(idx))
+}
+
+// Returns the derived values that use the parameter as a calc input
+func (t *ParamTableOf[E]) Children(idx E) []E {
+	t.checkBounds(idx)
+	return slices.Clone(t.getChildren(idx))
+}
+
+// Returns the other outputs written by the calc of a derived value
+func (t *ParamTableOf[E]) Siblings(idx E) []E {
+	t.checkBounds(idx)
+	var siblings []E
+	for _, sib := range t.getSiblings(idx) {
+		if sib != idx {
+			siblings = append(siblings, sib)
+		}
+	}
+	return siblings
+}
+
+// Returns every value the parameter is (transitively) calculated from, nearest first
+func (t *ParamTableOf[E]) Ancestors(idx E) []E {
+	t.checkBounds(idx)
+	return t.ancestors(idx)
+}
+
+// Returns every value that may be recalculated when the parameter changes, nearest first
+func (t *ParamTableOf[E]) Descendants(idx E) []E {
+	t.checkBounds(idx)
+	return t.descendants(idx)
+}
+
+// Returns the root values the parameter is ultimately calculated from, or the parameter
+// itself if it is not derived
+func (t *ParamTableOf[E]) RootsOf(idx E) []E {
+	t.checkBounds(idx)
+	if !t.isDerived(idx) {
+		return []E{idx}
+	}
+	var roots []E
+	for _, ancestor := range t.ancestors(idx) {
+		if !t.isDerived(ancestor) {
+			roots = append(roots, ancestor)
+		}
+	}
+	return roots
+}
+
+// Returns the calc of a derived value, ok is false if the parameter is not derived
+func (t *ParamTableOf[E]) CalcOf(idx E) (calcIdx PIdx_Calc, ok bool) {
+	t.checkBounds(idx)
+	if !t.isDerived(idx) {
+		return PIdx_Calc(^E(0)), false
+	}
+	return PIdx_Calc(t.hookupData[uint32(t.hookups[idx])+_HOOK_OFF_CALC]), true
+}
+
+// Returns the value type of the parameter
+func (t *ParamTableOf[E]) TypeOf(idx E) ParamType {
+	t.checkBounds(idx)
+	return ParamType(t.typeOfIdx(uint32(idx)))
+}
+
+// Returns the length of the longest chain of calcs from a root value to the parameter: 0 for
+// values that are not derived, 1 for values calculated only from roots, and so on
+func (t *ParamTableOf[E]) Depth(idx E) int {
+	t.checkBounds(idx)
+	depths := make([]int32, len(t.hookups))
+	for i := range depths {
+		depths[i] = -1
+	}
+	stack := []E{idx}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		if depths[n] >= 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		ready, depth := true, int32(0)
+		for _, parent := range t.getParents(n) {
+			if depths[parent] < 0 {
+				stack = append(stack, parent)
+				ready = false
+			} else {
+				depth = max(depth, depths[parent]+1)
+			}
+		}
+		if ready {
+			depths[n] = depth
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return int(depths[idx])
+}
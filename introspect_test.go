@@ -0,0 +1,81 @@
+package go_param_table
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestIntrospection(t *testing.T) {
+	t.Run("narrow", testIntrospection[uint16])
+	t.Run("wide", testIntrospection[uint32])
+}
+
+func testIntrospection[E Index](t *testing.T) {
+	// 0 -> (1, 2) -> 3 -> (4, 5) -> 6
+	g := newStackedDiamondGraph[E](2)
+	table := g.table
+	table.Configure(WithDebug(true), WithVerbosity(VerbositySilent))
+	var expectIdxs = func(name string, got []E, exp ...E) {
+		t.Helper()
+		slices.Sort(got)
+		if !slices.Equal(got, exp) {
+			t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", name, exp, got)
+		}
+	}
+	expectIdxs("parents", table.Parents(3), 1, 2)
+	expectIdxs("children", table.Children(0), 1, 2)
+	expectIdxs("ancestors", table.Ancestors(6), 0, 1, 2, 3, 4, 5)
+	expectIdxs("descendants", table.Descendants(2), 3, 4, 5, 6)
+	expectIdxs("roots", table.RootsOf(6), 0)
+	expectIdxs("roots of root", table.RootsOf(0), 0)
+	expectIdxs("siblings", table.Siblings(3))
+
+	if !table.IsRoot(0) || table.IsDerived(0) || !table.IsDerived(6) || table.IsRoot(6) {
+		t.Errorf("root/derived status error")
+	}
+	if !table.HasChildren(3) || table.HasChildren(6) {
+		t.Errorf("has children error")
+	}
+	if calc, ok := table.CalcOf(3); !ok || calc != _TEST_CALC_SUM {
+		t.Errorf("calc error:\n\tEXP: %d true\n\tGOT: %d %t", _TEST_CALC_SUM, calc, ok)
+	}
+	if _, ok := table.CalcOf(0); ok {
+		t.Errorf("root value reported a calc")
+	}
+	if got := table.TypeOf(3); got != TypeF64 || got.String() != "Float64" {
+		t.Errorf("type error:\n\tEXP: %v\n\tGOT: %v", TypeF64, got)
+	}
+	for idx, exp := range []int{0, 1, 1, 2, 3, 3, 4} {
+		if got := table.Depth(E(idx)); got != exp {
+			t.Errorf("depth of %d:\n\tEXP: %d\n\tGOT: %d", idx, exp, got)
+		}
+	}
+
+	// returned slices are copies
+	parents := table.Parents(3)
+	parents[0] = 6
+	expectIdxs("parents after modifying copy", table.Parents(3), 1, 2)
+	table.SetRoot_F64(g.root, 2)
+	if got := table.Get_F64(g.sink); got != 22 {
+		t.Errorf("value error:\n\tEXP: %f\n\tGOT: %f", 22.0, got)
+	}
+
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrIndexOutOfRange) {
+				t.Errorf("out of range:\n\tEXP: %v\n\tGOT: %v", ErrIndexOutOfRange, err)
+			}
+		}()
+		table.Children(100)
+	}()
+
+	typed := newTestBuilderOf[E]()
+	flag := typed.Bool("flag")
+	count := typed.U16("count")
+	built := typed.Build()
+	if built.TypeOf(E(*flag)) != TypeBool || built.TypeOf(E(*count)) != TypeU16 || built.IsInit(E(*flag)) {
+		t.Errorf("builder table type/init error")
+	}
+}
@@ -0,0 +1,231 @@
+package go_param_table
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"unsafe"
+)
+
+// Returns every initialized root value as a JSON object, in index order, keyed by the registered
+// name of the parameter or, if it has none, by its decimal index:
+//
+//	{"width":4,"height":3,"label":"box","7":true}
+//
+// Pointer and opaque values are not written, they only exist at runtime. Returns an
+// ErrValueRange error for a NaN or infinite float, which JSON cannot represent.
+//
+// Implements json.Marshaler, so a table can be a field of a config struct. As the method has a
+// pointer receiver, marshal a pointer to that struct (or make the field a *ParamTable)
+func (t *ParamTableOf[E]) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for idx := range t.hookups {
+		typeIdx := t.typeOfIdx(uint32(idx))
+		if !getFlag(uint32(idx), t.flags).IsInit() || t.isDerived(E(idx)) || !serializable(typeIdx) {
+			continue
+		}
+		if len(buf) > 1 {
+			buf = append(buf, ',')
+		}
+		key := t.Name(E(idx))
+		if key == "" {
+			key = strconv.Itoa(idx)
+		}
+		buf = appendJSONString(buf, key)
+		buf = append(buf, ':')
+		var err error
+		if buf, err = t.appendJSONValue(buf, uint32(idx), typeIdx); err != nil {
+			return nil, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// Reads a JSON object in the form written by MarshalJSON() and sets every root value in it as
+// one batch, initializing roots that were not yet initialized, so values derived from them are
+// recalculated once. Keys are looked up by registered name first, then as a decimal index.
+// Roots that are not in the object keep their value. Unlike SetRoot_*(), loading is not
+// recorded by the undo history.
+//
+// Every problem in the object is reported, joined with errors.Join(): an ErrUnknownName
+// error for a key that is neither a name nor an index, an ErrDerivedNotSettable error for a
+// derived value, an ErrWrongType error for a value of the wrong JSON type (or a pointer or
+// opaque parameter), and an ErrValueRange error for a number that does not fit the type of
+// its parameter. An ErrFormat error is returned if data is not a JSON object, and an
+// ErrBatchOpen error if a batch is open. The table is not modified if an error is returned
+func (t *ParamTableOf[E]) UnmarshalJSON(data []byte) error {
+	null := ^E(0)
+	if len(t.batch.starts) > 0 {
+		return newParamError(ErrBatchOpen, null, "UnmarshalJSON(): a batch is open, call CommitBatch() or Rollback() first")
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil || object == nil {
+		if err == nil {
+			err = errors.New("null is not an object")
+		}
+		return newParamError(ErrFormat, null, "UnmarshalJSON(): %s", err)
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	var errs []error
+	records := make([]loadRecord, 0, len(object))
+	for _, key := range keys {
+		idx, ok := t.LookupByName(key)
+		if !ok {
+			num, err := strconv.ParseUint(key, 10, 32)
+			if err != nil || num >= uint64(len(t.hookups)) {
+				errs = append(errs, newParamError(ErrUnknownName, null, "UnmarshalJSON(): %q is neither a parameter name nor an index (len %d)", key, len(t.hookups)))
+				continue
+			}
+			idx = E(num)
+		}
+		if t.isDerived(idx) {
+			errs = append(errs, newParamError(ErrDerivedNotSettable, idx, "UnmarshalJSON(): index %s is a derived value, cannot update directly", t.paramLabel(uint32(idx))))
+			continue
+		}
+		val, err := t.parseJSONValue(uint32(idx), object[key])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, loadRecord{idx: uint32(idx), val: val})
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	slices.SortFunc(records, func(a, b loadRecord) int {
+		return int(a.idx) - int(b.idx)
+	})
+	t.loadRecords(records, false)
+	return nil
+}
+
+func appendJSONString(buf []byte, str string) []byte {
+	quoted, _ := json.Marshal(str)
+	return append(buf, quoted...)
+}
+
+func (t *ParamTableOf[E]) appendJSONValue(buf []byte, idx uint32, typeIdx int) ([]byte, error) {
+	memPtr, subIdx := t.getBytePtr(idx, typeIdx)
+	ptr := unsafe.Pointer(memPtr)
+	switch typeIdx {
+	case typeStr:
+		return appendJSONString(buf, t.strs[subIdx]), nil
+	case typeBool:
+		return strconv.AppendBool(buf, *memPtr != 0), nil
+	case typeU64:
+		return strconv.AppendUint(buf, *(*uint64)(ptr), 10), nil
+	case typeU32:
+		return strconv.AppendUint(buf, uint64(*(*uint32)(ptr)), 10), nil
+	case typeU16:
+		return strconv.AppendUint(buf, uint64(*(*uint16)(ptr)), 10), nil
+	case typeU8:
+		return strconv.AppendUint(buf, uint64(*memPtr), 10), nil
+	case typeI64:
+		return strconv.AppendInt(buf, *(*int64)(ptr), 10), nil
+	case typeI32:
+		return strconv.AppendInt(buf, int64(*(*int32)(ptr)), 10), nil
+	case typeI16:
+		return strconv.AppendInt(buf, int64(*(*int16)(ptr)), 10), nil
+	case typeI8:
+		return strconv.AppendInt(buf, int64(*(*int8)(ptr)), 10), nil
+	}
+	val, bits := *(*float64)(ptr), 64
+	if typeIdx == typeF32 {
+		val, bits = float64(*(*float32)(ptr)), 32
+	}
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return nil, newParamError(ErrValueRange, idx, "MarshalJSON(): %s value %v of index %s cannot be represented in JSON", typeNames[typeIdx], val, t.paramLabel(idx))
+	}
+	return strconv.AppendFloat(buf, val, 'g', -1, bits), nil
+}
+
+// Parses raw into the layout saveValue() uses for the type of idx
+func (t *ParamTableOf[E]) parseJSONValue(idx uint32, raw json.RawMessage) (v savedValue, err error) {
+	typeIdx := t.typeOfIdx(idx)
+	wrongType := func(expected string) error {
+		return newParamError(ErrWrongType, idx, "UnmarshalJSON(): index %s is a %s value, expected %s, got %s", t.paramLabel(idx), typeNames[typeIdx], expected, raw)
+	}
+	outOfRange := func() error {
+		return newParamError(ErrValueRange, idx, "UnmarshalJSON(): %s is out of range for the %s value of index %s", raw, typeNames[typeIdx], t.paramLabel(idx))
+	}
+	ptr := unsafe.Pointer(&v.raw)
+	switch typeIdx {
+	case typePtr, typeVal:
+		return v, newParamError(ErrWrongType, idx, "UnmarshalJSON(): index %s is a %s value, which only exists at runtime", t.paramLabel(idx), typeNames[typeIdx])
+	case typeStr:
+		if json.Unmarshal(raw, &v.str) != nil || bytes.Equal(raw, []byte("null")) {
+			return v, wrongType("a string")
+		}
+		return v, nil
+	case typeBool:
+		var b bool
+		if json.Unmarshal(raw, &b) != nil || bytes.Equal(raw, []byte("null")) {
+			return v, wrongType("true or false")
+		}
+		if b {
+			v.raw[0] = 1
+		}
+		return v, nil
+	}
+	if len(raw) == 0 || (raw[0] != '-' && (raw[0] < '0' || raw[0] > '9')) {
+		return v, wrongType("a number")
+	}
+	bits := int(sizeTable[typeIdx]) * 8
+	switch typeIdx {
+	case typeF64, typeF32:
+		f, err := strconv.ParseFloat(string(raw), bits)
+		if err != nil {
+			return v, outOfRange()
+		}
+		if typeIdx == typeF32 {
+			*(*float32)(ptr) = float32(f)
+		} else {
+			*(*float64)(ptr) = f
+		}
+		return v, nil
+	case typeI64, typeI32, typeI16, typeI8:
+		i, err := strconv.ParseInt(string(raw), 10, bits)
+		if errors.Is(err, strconv.ErrRange) {
+			return v, outOfRange()
+		} else if err != nil {
+			return v, wrongType("an integer")
+		}
+		storeInt(ptr, uint64(i), bits)
+		return v, nil
+	}
+	if raw[0] == '-' {
+		if _, err := strconv.ParseInt(string(raw), 10, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+			return v, outOfRange()
+		}
+		return v, wrongType("an integer")
+	}
+	u, err := strconv.ParseUint(string(raw), 10, bits)
+	if errors.Is(err, strconv.ErrRange) {
+		return v, outOfRange()
+	} else if err != nil {
+		return v, wrongType("an integer")
+	}
+	storeInt(ptr, u, bits)
+	return v, nil
+}
+
+// Stores the low bits of val at ptr, as an integer of that size
+func storeInt(ptr unsafe.Pointer, val uint64, bits int) {
+	switch bits {
+	case 64:
+		*(*uint64)(ptr) = val
+	case 32:
+		*(*uint32)(ptr) = uint32(val)
+	case 16:
+		*(*uint16)(ptr) = uint16(val)
+	default:
+		*(*uint8)(ptr) = uint8(val)
+	}
+}
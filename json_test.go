@@ -0,0 +1,141 @@
+package go_param_table
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Run("narrow", testJSONRoundTrip[uint16])
+	t.Run("wide", testJSONRoundTrip[uint32])
+}
+
+func testJSONRoundTrip[E Index](t *testing.T) {
+	src := newBinaryTestTable[E]()
+	src.table.SetRoot_F64(src.width, 4)
+	src.table.SetRoot_U8(src.count, 200)
+	src.table.SetRoot_I16(src.offset, -300)
+	src.table.InitRoot_Bool(src.enabled, true, false)
+	data, err := json.Marshal(&src.table)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	exp := `{"width":4,"height":3,"offset":-300,"count":200,"enabled":true}`
+	if string(data) != exp {
+		t.Errorf("marshal:\n\tEXP: %s\n\tGOT: %s", exp, data)
+	}
+
+	dst := newBinaryTestTable[E]()
+	*dst.evals = 0
+	if err := json.Unmarshal([]byte(`{"width": 4, "count": 200, "offset": -300, "enabled": true}`), &dst.table); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	dst.expect(t, "unmarshal", 4, 3, "12 px²", 200, true, -300)
+	if *dst.evals != 2 {
+		t.Errorf("unmarshal: %d calcs evaluated for 2 changed derived values", *dst.evals)
+	}
+
+	// parameters without a name are keyed by index
+	table, _ := newF64TestTableOf[E](3)
+	table.InitRoot_F64(0, 1.5, false)
+	table.InitRoot_F64(2, -2e-9, false)
+	table.SetMeta(2, ParamMeta{Name: "tiny"})
+	if data, _ := json.Marshal(table); string(data) != `{"0":1.5,"tiny":-2e-09}` {
+		t.Errorf("index keys:\n\tEXP: %s\n\tGOT: %s", `{"0":1.5,"tiny":-2e-09}`, data)
+	}
+	if err := json.Unmarshal([]byte(`{"1":7,"tiny":3}`), table); err != nil || table.Get_F64(1) != 7 || table.Get_F64(2) != 3 {
+		t.Errorf("index keys: %v, %f, %f", err, table.Get_F64(1), table.Get_F64(2))
+	}
+
+	var saved, loaded struct {
+		Title  string           `json:"title"`
+		Params *ParamTableOf[E] `json:"params"`
+	}
+	stream := newBinaryTestTable[E]()
+	var buf bytes.Buffer
+	saved.Title, saved.Params = "box", &src.table
+	json.NewEncoder(&buf).Encode(saved)
+	loaded.Params = &stream.table
+	if err := json.NewDecoder(&buf).Decode(&loaded); err != nil || loaded.Title != "box" {
+		t.Fatalf("config struct: %v, %q", err, loaded.Title)
+	}
+	stream.expect(t, "config struct", 4, 3, "12 px²", 200, true, -300)
+}
+
+func TestJSONErrors(t *testing.T) {
+	t.Run("narrow", testJSONErrors[uint16])
+	t.Run("wide", testJSONErrors[uint32])
+}
+
+func testJSONErrors[E Index](t *testing.T) {
+	dst := newBinaryTestTable[E]()
+	before := dst.table.Snapshot()
+	err := dst.table.UnmarshalJSON([]byte(`{
+		"nope": 1,
+		"99": 1,
+		"area": 2,
+		"count": 256,
+		"offset": "x",
+		"height": 1e400,
+		"enabled": 1,
+		"data": null,
+		"width": 5
+	}`))
+	for _, kind := range []error{ErrUnknownName, ErrDerivedNotSettable, ErrValueRange, ErrWrongType} {
+		if !errors.Is(err, kind) {
+			t.Errorf("every problem reported:\n\tEXP: %v\n\tGOT: %v", kind, err)
+		}
+	}
+	var problems interface{ Unwrap() []error }
+	if !errors.As(err, &problems) || len(problems.Unwrap()) != 8 {
+		t.Errorf("every problem reported:\n\tEXP: 8 errors\n\tGOT: %v", err)
+	}
+	if !bytes.Equal(dst.table.values, before.values) || !slices.Equal(dst.table.flags, before.flags) {
+		t.Errorf("failed import modified the table")
+	}
+
+	for doc, kind := range map[string]error{
+		`{"count": -1}`:                   ErrValueRange,
+		`{"offset": 1.5}`:                 ErrWrongType,
+		`{"width": "4"}`:                  ErrWrongType,
+		`{"label": "derived"}`:            ErrDerivedNotSettable,
+		`[1, 2]`:                          ErrFormat,
+		`null`:                            ErrFormat,
+		`{"width": 4`:                     ErrFormat,
+		`{"width": 4, "enabled": "true"}`: ErrWrongType,
+	} {
+		if err := dst.table.UnmarshalJSON([]byte(doc)); !errors.Is(err, kind) {
+			t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", doc, kind, err)
+		}
+	}
+
+	dst.table.SetRoot_F64(dst.width, math.Inf(1))
+	if _, err := dst.table.MarshalJSON(); !errors.Is(err, ErrValueRange) {
+		t.Errorf("infinite float:\n\tEXP: %v\n\tGOT: %v", ErrValueRange, err)
+	}
+}
+
+func TestJSONHistory(t *testing.T) {
+	t.Run("narrow", testJSONHistory[uint16])
+	t.Run("wide", testJSONHistory[uint32])
+}
+
+func testJSONHistory[E Index](t *testing.T) {
+	g := newBinaryTestTable[E]()
+	g.table.EnableHistory()
+	for _, width := range []float64{5, 6, 7} {
+		g.table.SetRoot_F64(g.width, width)
+	}
+	if err := json.Unmarshal([]byte(`{"width": 8, "count": 4}`), &g.table); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(g.table.history.entries) != 3 {
+		t.Errorf("load recorded by the undo history: %d entries", len(g.table.history.entries))
+	}
+	g.table.Undo()
+	g.expect(t, "undo after load", 6, 3, "18 px²", 4, false, -1)
+}
@@ -0,0 +1,161 @@
+package go_param_table
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// Optional descriptive information about a parameter. None of it affects how values are stored
+// or updated, but a registered Name is used in place of the bare index in every diagnostic
+type ParamMeta struct {
+	Name        string
+	Description string
+	Unit        string
+	Group       string
+	// Free-form labels, for example to select parameters with Watch(). Must not be modified
+	// after being passed to SetMeta()
+	Tags []string
+}
+
+// An option passed to RegisterCalc()
+type CalcOption func(c *calcConfig)
+
+type calcConfig struct {
+	name     string
+	parallel bool
+}
+
+// Names the calc in diagnostics
+func WithCalcName(name string) CalcOption {
+	return func(c *calcConfig) {
+		c.name = name
+	}
+}
+
+// Metadata is only allocated once the first parameter (or calc) is given some, so tables that
+// never use it pay for three nil fields only
+type metaRegistry[E Index] struct {
+	params    []ParamMeta
+	byName    map[string]E
+	calcNames []string
+}
+
+func (m *metaRegistry[E]) memoryFootprint() uintptr {
+	size := uintptr(cap(m.params)) * unsafe.Sizeof(ParamMeta{})
+	size += uintptr(cap(m.calcNames)) * unsafe.Sizeof("")
+	for _, meta := range m.params {
+		size += uintptr(len(meta.Name) + len(meta.Description) + len(meta.Unit) + len(meta.Group))
+		size += uintptr(cap(meta.Tags)) * unsafe.Sizeof("")
+		for _, tag := range meta.Tags {
+			size += uintptr(len(tag))
+		}
+	}
+	for _, name := range m.calcNames {
+		size += uintptr(len(name))
+	}
+	// rough map estimate: one key header and one value per entry
+	size += uintptr(len(m.byName)) * (unsafe.Sizeof("") + unsafe.Sizeof(E(0)))
+	return size
+}
+
+// Replaces all metadata of the parameter. Panics if the index is out of range, or its name is
+// already used by another parameter or is a decimal number, which would read like an index in
+// diagnostics and JSON keys (see TrySetMeta())
+func (t *ParamTableOf[E]) SetMeta(idx E, meta ParamMeta) {
+	if err := t.TrySetMeta(idx, meta); err != nil {
+		t.fail(err)
+	}
+}
+
+// Same as SetMeta(), but returns an ErrIndexOutOfRange, ErrDuplicateName or ErrInvalidName error
+// instead of panicking
+func (t *ParamTableOf[E]) TrySetMeta(idx E, meta ParamMeta) error {
+	if int(idx) >= len(t.hookups) {
+		return newParamError(ErrIndexOutOfRange, idx, "SetMeta(): index %d is outside bounds of parameter list (len %d)", idx, len(t.hookups))
+	}
+	if isIndexName(meta.Name) {
+		return newParamError(ErrInvalidName, idx, "SetMeta(): name %q of index %d is a number, which reads like an index", meta.Name, idx)
+	}
+	if other, used := t.meta.byName[meta.Name]; used && meta.Name != "" && other != idx {
+		return newParamError(ErrDuplicateName, idx, "SetMeta(): name %q of index %d is already used by index %d", meta.Name, idx, other)
+	}
+	if t.meta.params == nil {
+		t.meta.params = make([]ParamMeta, len(t.hookups))
+		t.meta.byName = make(map[string]E)
+	}
+	if old := t.meta.params[idx].Name; old != "" {
+		delete(t.meta.byName, old)
+	}
+	if meta.Name != "" {
+		t.meta.byName[meta.Name] = idx
+	}
+	t.meta.params[idx] = meta
+	return nil
+}
+
+// Returns the metadata of the parameter, or the zero ParamMeta if none was set
+func (t *ParamTableOf[E]) Meta(idx E) ParamMeta {
+	if int(idx) >= len(t.meta.params) {
+		return ParamMeta{}
+	}
+	return t.meta.params[idx]
+}
+
+// Returns the name of the parameter, or "" if it has none
+func (t *ParamTableOf[E]) Name(idx E) string {
+	return t.Meta(idx).Name
+}
+
+// Returns the index of the parameter with the given name
+func (t *ParamTableOf[E]) LookupByName(name string) (idx E, ok bool) {
+	if name == "" {
+		return ^E(0), false
+	}
+	idx, ok = t.meta.byName[name]
+	if !ok {
+		return ^E(0), false
+	}
+	return idx, true
+}
+
+// Returns the name the calc was registered with, or "" if it has none
+func (t *ParamTableOf[E]) CalcName(calcIdx PIdx_Calc) string {
+	if int(calcIdx) >= len(t.meta.calcNames) {
+		return ""
+	}
+	return t.meta.calcNames[calcIdx]
+}
+
+func (t *ParamTableOf[E]) setCalcName(calcIdx PIdx_Calc, name string) {
+	if name == "" && t.meta.calcNames == nil {
+		return
+	}
+	if t.meta.calcNames == nil {
+		t.meta.calcNames = make([]string, len(t.calcs))
+	}
+	t.meta.calcNames[calcIdx] = name
+}
+
+// Whether the name parses as an unsigned decimal number. Such names are rejected, since unnamed
+// parameters are labelled and keyed by their index
+func isIndexName(name string) bool {
+	_, err := strconv.ParseUint(name, 10, 64)
+	return err == nil
+}
+
+// The index as printed in diagnostics, "12" or "12 (rect.area)" if it has a name
+func (t *ParamTableOf[E]) paramLabel(idx uint32) string {
+	label := strconv.FormatUint(uint64(idx), 10)
+	if int(idx) < len(t.meta.params) && t.meta.params[idx].Name != "" {
+		label += " (" + t.meta.params[idx].Name + ")"
+	}
+	return label
+}
+
+func (t *ParamTableOf[E]) calcLabel(calcIdx PIdx_Calc) string {
+	label := strconv.FormatUint(uint64(calcIdx), 10)
+	if name := t.CalcName(calcIdx); name != "" {
+		label += " (" + name + ")"
+	}
+	return label
+}
@@ -0,0 +1,99 @@
+package go_param_table
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParamMeta(t *testing.T) {
+	t.Run("narrow", testParamMeta[uint16])
+	t.Run("wide", testParamMeta[uint32])
+}
+
+func testParamMeta[E Index](t *testing.T) {
+	const (
+		A PIdx_F64 = iota
+		B
+		C
+		_F64_PARAMS_END
+	)
+	var out bytes.Buffer
+	table, _ := newF64TestTableOf[E](uint32(_F64_PARAMS_END), WithDebug(true), WithDebugWriter(&out))
+	if _, ok := table.LookupByName("rect.width"); ok || table.Name(E(A)) != "" {
+		t.Errorf("table without metadata reported a name")
+	}
+	width := ParamMeta{Name: "rect.width", Description: "width of the rectangle", Unit: "px", Group: "rect", Tags: []string{"size"}}
+	table.SetMeta(E(A), width)
+	table.SetMeta(E(B), ParamMeta{Name: "rect.area"})
+	if got := table.Meta(E(A)); !reflect.DeepEqual(got, width) {
+		t.Errorf("meta error:\n\tEXP: %+v\n\tGOT: %+v", width, got)
+	}
+	if idx, ok := table.LookupByName("rect.area"); !ok || idx != E(B) {
+		t.Errorf("lookup error:\n\tEXP: %d true\n\tGOT: %d %t", B, idx, ok)
+	}
+	if err := table.TrySetMeta(E(C), ParamMeta{Name: "rect.width"}); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("duplicate name:\n\tEXP: %v\n\tGOT: %v", ErrDuplicateName, err)
+	}
+	// a numeric name would be indistinguishable from the index of an unnamed parameter
+	for _, name := range []string{"12", "0"} {
+		if err := table.TrySetMeta(E(C), ParamMeta{Name: name}); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("numeric name %q:\n\tEXP: %v\n\tGOT: %v", name, ErrInvalidName, err)
+		}
+	}
+	if _, ok := table.LookupByName("12"); ok {
+		t.Errorf("rejected numeric name was registered")
+	}
+	if err := table.TrySetMeta(100, ParamMeta{Name: "x"}); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("out of range:\n\tEXP: %v\n\tGOT: %v", ErrIndexOutOfRange, err)
+	}
+	// renaming frees the old name
+	table.SetMeta(E(B), ParamMeta{Name: "rect.size"})
+	if _, ok := table.LookupByName("rect.area"); ok {
+		t.Errorf("old name still resolves after rename")
+	}
+	table.SetMeta(E(C), ParamMeta{Name: "rect.area"})
+
+	// diagnostics print the name next to the index
+	_, err := table.TryGet_F64(C)
+	if err == nil || !strings.Contains(err.Error(), "2 (rect.area)") {
+		t.Errorf("uninit error does not name the parameter: %v", err)
+	}
+	table.InitRoot_F64(A, 1, false)
+	derive_F64(table, E(B), _TEST_CALC_ADD_ONE, E(A))
+	func() {
+		defer func() { recover() }()
+		table.InitDerived_F64(A, false, _TEST_CALC_ADD_ONE, []E{E(B)}, []E{E(A)})
+	}()
+	if path := "0 (rect.width) -> 1 (rect.size) -> 0 (rect.width)"; !strings.Contains(out.String(), path) {
+		t.Errorf("cycle report did not contain path %q:\n\t%s", path, out.String())
+	}
+
+	named := newTestTableOf[E](ParamLayout{}, 1, WithDebug(true), WithVerbosity(VerbositySilent))
+	named.RegisterCalc(0, func(c *CalcInterfaceOf[E]) {}, WithCalcName("noop"))
+	if named.CalcName(0) != "noop" {
+		t.Errorf("calc name error:\n\tEXP: %q\n\tGOT: %q", "noop", named.CalcName(0))
+	}
+	if err := named.TryRegisterCalc(0, func(c *CalcInterfaceOf[E]) {}); err == nil || !strings.Contains(err.Error(), "0 (noop)") {
+		t.Errorf("calc error does not name the calc: %v", err)
+	}
+
+	// names declared with a TableBuilder are registered on the built table
+	b := newTestBuilderOf[E]()
+	height := b.F32("rect.height")
+	calc := b.Calc("area", nil)
+	built := b.Build()
+	if idx, ok := built.LookupByName("rect.height"); !ok || idx != E(*height) {
+		t.Errorf("builder name lookup error:\n\tEXP: %d true\n\tGOT: %d %t", *height, idx, ok)
+	}
+	if built.CalcName(*calc) != "" {
+		t.Errorf("unregistered builder calc was named")
+	}
+	numeric := newTestBuilderOf[E]()
+	numeric.F64("7")
+	if _, err := numeric.TryBuild(); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("builder numeric name:\n\tEXP: %v\n\tGOT: %v", ErrInvalidName, err)
+	}
+}
@@ -0,0 +1,83 @@
+package go_param_table
+
+import (
+	"fmt"
+	"io"
+)
+
+// How much a ParamTable writes to its debug writer
+type Verbosity uint8
+
+const (
+	// Nothing is written. Failed safety checks still panic with a *ParamError
+	VerbositySilent Verbosity = iota
+	// Only failed safety checks are written, just before panicking
+	VerbosityFatal
+	// Failed safety checks and non-fatal warnings are written (default)
+	VerbosityWarn
+)
+
+type debugConfig struct {
+	enabled   bool
+	writer    io.Writer
+	verbosity Verbosity
+}
+
+func defaultDebugConfig() debugConfig {
+	return debugConfig{
+		enabled:   EnableDebug,
+		writer:    DebugWriter,
+		verbosity: VerbosityWarn,
+	}
+}
+
+// An option passed to NewParamTable() or ParamTable.Configure()
+type TableOption func(d *debugConfig)
+
+// Enables or disables the additional safety checks for this table only (defaults to EnableDebug)
+func WithDebug(enabled bool) TableOption {
+	return func(d *debugConfig) {
+		d.enabled = enabled
+	}
+}
+
+// Sets the writer debug messages for this table are written to (defaults to DebugWriter)
+func WithDebugWriter(w io.Writer) TableOption {
+	return func(d *debugConfig) {
+		d.writer = w
+	}
+}
+
+// Sets how much this table writes to its debug writer (defaults to VerbosityWarn)
+func WithVerbosity(v Verbosity) TableOption {
+	return func(d *debugConfig) {
+		d.verbosity = v
+	}
+}
+
+// Applies options to an existing table, for example to turn off debug checks once
+// initialization has been verified
+func (t *ParamTableOf[E]) Configure(opts ...TableOption) {
+	for _, opt := range opts {
+		opt(&t.debug)
+	}
+}
+
+// Whether the additional safety checks are enabled for this table
+func (t *ParamTableOf[E]) DebugEnabled() bool {
+	return t.debug.enabled
+}
+
+// Reports a failed safety check: writes it to the debug writer, then panics with the error itself
+func (t *ParamTableOf[E]) fail(err error) {
+	if t.debug.verbosity >= VerbosityFatal && t.debug.writer != nil {
+		fmt.Fprintf(t.debug.writer, "fatal: %s", err)
+	}
+	panic(err)
+}
+
+func (t *ParamTableOf[E]) warnf(format string, args ...any) {
+	if t.debug.enabled && t.debug.verbosity >= VerbosityWarn && t.debug.writer != nil {
+		fmt.Fprintf(t.debug.writer, "warn: go_param_table: "+format, args...)
+	}
+}
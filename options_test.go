@@ -0,0 +1,62 @@
+package go_param_table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPerTableDebugConfig(t *testing.T) {
+	t.Run("narrow", testPerTableDebugConfig[uint16])
+	t.Run("wide", testPerTableDebugConfig[uint32])
+}
+
+func testPerTableDebugConfig[E Index](t *testing.T) {
+	var strictOut, quietOut bytes.Buffer
+	strict, _ := newF64TestTableOf[E](2, WithDebug(true), WithDebugWriter(&strictOut))
+	fast, _ := newF64TestTableOf[E](2, WithDebug(false), WithDebugWriter(&quietOut))
+	strict.InitRoot_F64(0, 1, false)
+	fast.InitRoot_F64(0, 1, false)
+
+	// flipping the global default must not affect existing tables
+	prevDebug := EnableDebug
+	EnableDebug = !prevDebug
+	defer func() {
+		EnableDebug = prevDebug
+	}()
+	if !strict.DebugEnabled() || fast.DebugEnabled() {
+		t.Errorf("global EnableDebug changed the debug setting of existing tables")
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("getting uninit val did not cause panic on table created WithDebug(true)")
+			}
+		}()
+		strict.Get_F64(1)
+	}()
+	if !strings.HasPrefix(strictOut.String(), "fatal: go_param_table:") {
+		t.Errorf("failed safety check was not written to the table's debug writer: %q", strictOut.String())
+	}
+	// unchecked on the fast table
+	fast.Get_F64(1)
+
+	// silent tables still panic, but write nothing
+	strictOut.Reset()
+	strict.Configure(WithVerbosity(VerbositySilent))
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("getting uninit val did not cause panic with VerbositySilent")
+			}
+		}()
+		strict.Get_F64(1)
+	}()
+	if strictOut.Len() != 0 {
+		t.Errorf("table with VerbositySilent wrote to its debug writer: %q", strictOut.String())
+	}
+	if quietOut.Len() != 0 {
+		t.Errorf("table with debug disabled wrote to its debug writer: %q", quietOut.String())
+	}
+}
@@ -0,0 +1,251 @@
+package go_param_table
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Marks the calc as safe to run concurrently with other calcs when parallel propagation is
+// enabled (see EnableParallelPropagation()): it only reads its inputs, only writes its outputs,
+// and any state it captures is safe for concurrent use
+func WithParallelSafe() CalcOption {
+	return func(c *calcConfig) {
+		c.parallel = true
+	}
+}
+
+// Scratch state of parallel propagation, reused on every update
+type parallelState[E Index] struct {
+	workers int
+	// the address of the table that started pool. A copy of the table made by value shares the
+	// state, and replaces it with its own before using it (see ownParallel()). Not a pointer, so
+	// that the table and its state do not form a cycle, which would keep the finalizer from running
+	owner uintptr
+	// feeds the workers-1 helper goroutines, which live until parallel propagation is disabled.
+	// Each func received runs one share of a level
+	pool chan func()
+	// the derived values of the current level that must be evaluated
+	jobs []E
+	// the outputs changed by each parallel job, merged in job order once the level is done
+	changed [][]E
+}
+
+func (p *parallelState[E]) memoryFootprint() uintptr {
+	if p == nil {
+		return 0
+	}
+	size := unsafe.Sizeof(*p)
+	size += uintptr(cap(p.jobs)) * unsafe.Sizeof(E(0))
+	size += uintptr(cap(p.changed)) * unsafe.Sizeof([]E(nil))
+	for _, changed := range p.changed {
+		size += uintptr(cap(changed)) * unsafe.Sizeof(E(0))
+	}
+	return size
+}
+
+// Evaluates the derived values of each level of the dependency graph (the values whose every
+// dirty parent is in an earlier level) concurrently on up to workers goroutines, the calling
+// one included. workers <= 0 uses runtime.GOMAXPROCS(0).
+//
+// Only calcs registered with WithParallelSafe() run concurrently, the others still run one at a
+// time on the calling goroutine. A level is evaluated serially if it has fewer than two
+// parallel-safe calcs to run, or if two of them write the same output. The resulting values are
+// the same as with serial propagation, but values within a level may be recalculated (and their
+// listeners called) in a different order.
+//
+// The helper goroutines are started here and wait for work between updates. They are stopped by
+// DisableParallelPropagation(), or once the table is garbage collected. A copy of the table made
+// by value (or the table moved to another address) starts its own helpers on its first parallel
+// update, so disabling one copy never stops the helpers of another. A calc that panics fails
+// the update on the calling goroutine once its level is done, and leaves the state of that update
+// as undefined as a panic during serial propagation does
+func (t *ParamTableOf[E]) EnableParallelPropagation(workers int) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if t.parallel != nil && t.parallel.workers == workers {
+		return
+	}
+	t.DisableParallelPropagation()
+	t.parallel = newParallelState(t, workers)
+}
+
+// Returns to evaluating every derived value on the calling goroutine, and stops the helper
+// goroutines
+func (t *ParamTableOf[E]) DisableParallelPropagation() {
+	if t.parallel == nil {
+		return
+	}
+	// the helpers of a state shared with another copy are left to that copy
+	if t.parallel.owner == uintptr(unsafe.Pointer(t)) {
+		runtime.SetFinalizer(t.parallel, nil)
+		t.parallel.stop()
+	}
+	t.parallel = nil
+}
+
+func newParallelState[E Index](owner *ParamTableOf[E], workers int) *parallelState[E] {
+	par := &parallelState[E]{workers: workers, owner: uintptr(unsafe.Pointer(owner)), pool: make(chan func(), workers)}
+	for w := 1; w < workers; w += 1 {
+		// only the channel is captured, so that an abandoned table can still be collected
+		go func(pool chan func()) {
+			for work := range pool {
+				work()
+			}
+		}(par.pool)
+	}
+	runtime.SetFinalizer(par, (*parallelState[E]).stop)
+	return par
+}
+
+func (p *parallelState[E]) stop() {
+	close(p.pool)
+}
+
+// Returns the parallel state of the table, replacing a state started by another table first.
+// The replaced state keeps running for the table that started it, or is stopped by its finalizer
+// once that table is gone
+func (t *ParamTableOf[E]) ownParallel() *parallelState[E] {
+	if t.parallel.owner != uintptr(unsafe.Pointer(t)) {
+		t.parallel = newParallelState(t, t.parallel.workers)
+	}
+	return t.parallel
+}
+
+// Whether the calc was registered with WithParallelSafe()
+func (t *ParamTableOf[E]) IsCalcParallelSafe(calcIdx PIdx_Calc) bool {
+	return int(calcIdx) < len(t.parallelCalcs) && t.parallelCalcs[calcIdx]
+}
+
+func (t *ParamTableOf[E]) setCalcParallel(calcIdx PIdx_Calc, parallel bool) {
+	if !parallel && t.parallelCalcs == nil {
+		return
+	}
+	if t.parallelCalcs == nil {
+		t.parallelCalcs = make([]bool, len(t.calcs))
+	}
+	t.parallelCalcs[calcIdx] = parallel
+}
+
+// The level-by-level counterpart of the serial loop of evaluateChanged(). The queue holds the
+// dirty values without dirty parents
+func (t *ParamTableOf[E]) evaluateLevels() {
+	p := &t.prop
+	for start := 0; start < len(p.queue); {
+		end := len(p.queue)
+		t.evaluateLevel(p.queue[start:end])
+		for _, idx := range p.queue[start:end] {
+			t.forEachSuccessor(idx, func(child E) {
+				if p.marks[child]&_PROP_DIRTY != 0 {
+					p.indeg[child] -= 1
+					if p.indeg[child] == 0 {
+						p.queue = append(p.queue, child)
+					}
+				}
+			})
+		}
+		start = end
+	}
+}
+
+func (t *ParamTableOf[E]) evaluateLevel(level []E) {
+	par := t.ownParallel()
+	par.jobs = par.jobs[:0]
+	for _, idx := range level {
+		if t.prop.marks[idx]&_PROP_DONE == 0 && t.anyParentChanged(idx) {
+			t.markSiblingsDone(idx)
+			par.jobs = append(par.jobs, idx)
+		}
+	}
+	if !t.levelRunsInParallel(par.jobs) {
+		for _, idx := range par.jobs {
+			t.trigger(idx)
+		}
+		return
+	}
+	for len(par.changed) < len(par.jobs) {
+		par.changed = append(par.changed, nil)
+	}
+	for i := range par.jobs {
+		par.changed[i] = par.changed[i][:0]
+	}
+	var next atomic.Int32
+	var wg sync.WaitGroup
+	var failed sync.Once
+	var failure any
+	work := func() {
+		defer wg.Done()
+		for {
+			i := int(next.Add(1) - 1)
+			if i >= len(par.jobs) {
+				return
+			}
+			if !t.isParallelJob(par.jobs[i]) {
+				continue
+			}
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						failed.Do(func() { failure = r })
+					}
+				}()
+				t.triggerInto(par.jobs[i], &par.changed[i])
+			}()
+		}
+	}
+	workers := min(par.workers, len(par.jobs))
+	wg.Add(workers)
+	for w := 1; w < workers; w += 1 {
+		par.pool <- work
+	}
+	work()
+	wg.Wait()
+	if failure != nil {
+		panic(failure)
+	}
+	// in job order, so that the changes are merged the same way on every run
+	for i, idx := range par.jobs {
+		if !t.isParallelJob(idx) {
+			t.trigger(idx)
+			continue
+		}
+		for _, changed := range par.changed[i] {
+			t.markChanged(changed)
+		}
+	}
+}
+
+func (t *ParamTableOf[E]) isParallelJob(idx E) bool {
+	return t.IsCalcParallelSafe(PIdx_Calc(t.hookupData[uint32(t.hookups[idx])+_HOOK_OFF_CALC]))
+}
+
+// Whether the level has at least two parallel-safe jobs, and no two jobs write the same output
+func (t *ParamTableOf[E]) levelRunsInParallel(jobs []E) bool {
+	safe := 0
+	for _, idx := range jobs {
+		if t.isParallelJob(idx) {
+			safe += 1
+		}
+	}
+	if safe < 2 {
+		return false
+	}
+	marks := t.prop.marks
+	disjoint := true
+	for _, idx := range jobs {
+		for _, out := range t.getSiblings(idx) {
+			if marks[out]&_PROP_CLAIMED != 0 {
+				disjoint = false
+			}
+			marks[out] |= _PROP_CLAIMED
+		}
+	}
+	for _, idx := range jobs {
+		for _, out := range t.getSiblings(idx) {
+			marks[out] &^= _PROP_CLAIMED
+		}
+	}
+	return disjoint
+}
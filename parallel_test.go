@@ -0,0 +1,235 @@
+package go_param_table
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const _PARALLEL_BRANCHES = 8
+
+type parallelTestTable[E Index] struct {
+	table   ParamTableOf[E]
+	evals   *atomic.Int32
+	active  *atomic.Int32
+	overlap *atomic.Int32
+	delay   *atomic.Int64
+	seed    PIdx_F64
+	factors [_PARALLEL_BRANCHES]PIdx_F64
+}
+
+// seed * factor[i] -> mid[i] -> out[i] -> label[i], all mid -> lo, hi (one calc), all out -> total
+func newParallelTestTable[E Index]() parallelTestTable[E] {
+	g := parallelTestTable[E]{evals: new(atomic.Int32), active: new(atomic.Int32), overlap: new(atomic.Int32), delay: new(atomic.Int64)}
+	b := newTestBuilderOf[E](WithDebug(true), WithVerbosity(VerbositySilent))
+	seed, lo, hi, total := b.F64("seed"), b.F64("lo"), b.F64("hi"), b.F64("total")
+	var factors, mids, outs [_PARALLEL_BRANCHES]*PIdx_F64
+	var labels [_PARALLEL_BRANCHES]*PIdx_Str
+	for i := range factors {
+		factors[i], mids[i], outs[i] = b.F64(fmt.Sprint("factor", i)), b.F64(fmt.Sprint("mid", i)), b.F64(fmt.Sprint("out", i))
+		labels[i] = b.Str(fmt.Sprint("label", i))
+	}
+	// tracks how many calcs run at once
+	var run = func(fn func()) {
+		g.evals.Add(1)
+		if g.active.Add(1) > 1 {
+			g.overlap.Add(1)
+		}
+		time.Sleep(time.Duration(g.delay.Load()))
+		fn()
+		g.active.Add(-1)
+	}
+	calcMul := b.Calc("mul", func(c *CalcInterfaceOf[E]) {
+		run(func() {
+			if c.GetInput_F64(0) < 0 {
+				panic("negative seed")
+			}
+			c.SetOutput_F64(0, c.GetInput_F64(0)*c.GetInput_F64(1))
+		})
+	}, WithParallelSafe())
+	calcAddOne := b.Calc("addOne", func(c *CalcInterfaceOf[E]) {
+		run(func() { c.SetOutput_F64(0, c.GetInput_F64(0)+1) })
+	}, WithParallelSafe())
+	calcRange := b.Calc("range", func(c *CalcInterfaceOf[E]) {
+		run(func() {
+			lo, hi := c.GetInput_F64(0), c.GetInput_F64(0)
+			for i := range c.inputs {
+				lo, hi = min(lo, c.GetInput_F64(uint16(i))), max(hi, c.GetInput_F64(uint16(i)))
+			}
+			c.SetOutput_F64(0, lo)
+			c.SetOutput_F64(1, hi)
+		})
+	}, WithParallelSafe())
+	calcLabel := b.Calc("label", func(c *CalcInterfaceOf[E]) {
+		g.evals.Add(1)
+		c.SetOutput_Str(0, fmt.Sprintf("%.2f", c.GetInput_F64(0)))
+	})
+	calcSum := b.Calc("sum", func(c *CalcInterfaceOf[E]) {
+		g.evals.Add(1)
+		sum := 0.0
+		for i := range c.inputs {
+			sum += c.GetInput_F64(uint16(i))
+		}
+		c.SetOutput_F64(0, sum)
+	})
+	g.table = b.Build()
+	g.seed = *seed
+	g.table.InitRoot_F64(g.seed, 1, false)
+	var midIdxs, outIdxs []E
+	for i := range factors {
+		g.factors[i] = *factors[i]
+		g.table.InitRoot_F64(g.factors[i], float64(i+1), false)
+		g.table.InitDerived_F64(*mids[i], false, *calcMul, []E{E(g.seed), E(g.factors[i])}, []E{E(*mids[i])})
+		g.table.InitDerived_F64(*outs[i], false, *calcAddOne, []E{E(*mids[i])}, []E{E(*outs[i])})
+		g.table.InitDerived_Str(*labels[i], false, *calcLabel, []E{E(*outs[i])}, []E{E(*labels[i])})
+		midIdxs, outIdxs = append(midIdxs, E(*mids[i])), append(outIdxs, E(*outs[i]))
+	}
+	g.table.InitDerived_F64(*lo, false, *calcRange, midIdxs, []E{E(*lo), E(*hi)})
+	g.table.InitDerived_F64(*hi, false, *calcRange, midIdxs, []E{E(*lo), E(*hi)})
+	g.table.InitDerived_F64(*total, false, *calcSum, outIdxs, []E{E(*total)})
+	return g
+}
+
+func TestParallelPropagation(t *testing.T) {
+	t.Run("narrow", testParallelPropagation[uint16])
+	t.Run("wide", testParallelPropagation[uint32])
+}
+
+func testParallelPropagation[E Index](t *testing.T) {
+	serial, parallel := newParallelTestTable[E](), newParallelTestTable[E]()
+	parallel.table.EnableParallelPropagation(4)
+	if !parallel.table.IsCalcParallelSafe(0) || parallel.table.IsCalcParallelSafe(3) {
+		t.Errorf("parallel-safe calcs not registered")
+	}
+	var changes [2][]string
+	for i, g := range []*parallelTestTable[E]{&serial, &parallel} {
+		i := i
+		// total
+		Subscribe(&g.table, PIdx_F64(3), func(old, new float64) {
+			changes[i] = append(changes[i], fmt.Sprint(old, new))
+		})
+	}
+	rng := rand.New(rand.NewSource(1))
+	for step := 0; step < 200; step += 1 {
+		seed, factor, value := rng.Float64()*10, rng.Intn(_PARALLEL_BRANCHES), rng.Float64()*10
+		for _, g := range []*parallelTestTable[E]{&serial, &parallel} {
+			g.evals.Store(0)
+			if step%3 == 0 {
+				g.table.BeginBatch()
+				g.table.SetRoot_F64(g.seed, seed)
+				g.table.SetRoot_F64(g.factors[factor], value)
+				g.table.CommitBatch()
+			} else {
+				g.table.SetRoot_F64(g.seed, seed)
+			}
+		}
+		if !bytes.Equal(serial.table.values, parallel.table.values) || !slices.Equal(serial.table.strs, parallel.table.strs) {
+			t.Fatalf("step %d: parallel propagation computed different values", step)
+		}
+		if serial.evals.Load() != parallel.evals.Load() {
+			t.Fatalf("step %d: %d calcs evaluated in parallel, %d serially", step, parallel.evals.Load(), serial.evals.Load())
+		}
+	}
+	if !slices.Equal(changes[0], changes[1]) {
+		t.Errorf("listeners notified differently:\n\tSERIAL:   %v\n\tPARALLEL: %v", changes[0], changes[1])
+	}
+	if serial.overlap.Load() != 0 {
+		t.Errorf("serial propagation ran %d calcs concurrently", serial.overlap.Load())
+	}
+
+	// with calcs slow enough, independent branches run at the same time
+	parallel.delay.Store(int64(time.Millisecond))
+	parallel.table.SetRoot_F64(parallel.seed, 100)
+	if parallel.overlap.Load() == 0 {
+		t.Errorf("no calcs ran concurrently")
+	}
+	parallel.delay.Store(0)
+
+	// a panicking calc fails the update, the table stays usable
+	func() {
+		defer func() {
+			if r := recover(); r != "negative seed" {
+				t.Errorf("panic in parallel calc:\n\tEXP: negative seed\n\tGOT: %v", r)
+			}
+		}()
+		parallel.table.SetRoot_F64(parallel.seed, -1)
+	}()
+	parallel.table.SetRoot_F64(parallel.seed, 2)
+	serial.table.SetRoot_F64(serial.seed, 2)
+	if !bytes.Equal(serial.table.values, parallel.table.values) {
+		t.Errorf("parallel propagation after a panic computed different values")
+	}
+
+	clone := parallel.table.Clone()
+	parallel.table.DisableParallelPropagation()
+	if clone.parallel == nil || parallel.table.parallel != nil {
+		t.Errorf("parallel propagation not copied to the clone, or not disabled")
+	}
+	clone.DisableParallelPropagation()
+}
+
+// Waits for the number of goroutines to settle at exp
+func expectGoroutines(t *testing.T, name string, exp int) {
+	t.Helper()
+	got := runtime.NumGoroutine()
+	for i := 0; i < 100 && got != exp; i += 1 {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+		got = runtime.NumGoroutine()
+	}
+	if got != exp {
+		t.Errorf("%s:\n\tEXP: %d goroutines\n\tGOT: %d", name, exp, got)
+	}
+}
+
+func TestParallelWorkerPool(t *testing.T) {
+	t.Run("narrow", testParallelWorkerPool[uint16])
+	t.Run("wide", testParallelWorkerPool[uint32])
+}
+
+func testParallelWorkerPool[E Index](t *testing.T) {
+	// let the helpers of tables dropped by earlier tests stop first
+	before := -1
+	for i := 0; i < 100 && before != runtime.NumGoroutine(); i += 1 {
+		before = runtime.NumGoroutine()
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	g := newParallelTestTable[E]()
+	g.table.EnableParallelPropagation(4)
+	expectGoroutines(t, "enabled", before+3)
+	for i := 0; i < 50; i += 1 {
+		g.table.SetRoot_F64(g.seed, float64(i))
+	}
+	expectGoroutines(t, "after updates", before+3)
+	g.table.EnableParallelPropagation(2)
+	expectGoroutines(t, "fewer workers", before+1)
+	g.table.DisableParallelPropagation()
+	expectGoroutines(t, "disabled", before)
+
+	// a copy made by value starts its own helpers, disabling either copy leaves the other working
+	g.table.EnableParallelPropagation(4)
+	copied := g.table
+	g.table.DisableParallelPropagation()
+	copied.SetRoot_F64(g.seed, 100)
+	expectGoroutines(t, "copy after disabling the original", before+3)
+	g.table.EnableParallelPropagation(4)
+	copied.DisableParallelPropagation()
+	g.table.SetRoot_F64(g.seed, 101)
+	expectGoroutines(t, "original after disabling the copy", before+3)
+	g.table.DisableParallelPropagation()
+	expectGoroutines(t, "both disabled", before)
+
+	// the helpers of a table that is dropped while enabled stop once it is collected
+	func() {
+		dropped := newParallelTestTable[E]()
+		dropped.table.EnableParallelPropagation(4)
+		dropped.table.SetRoot_F64(dropped.seed, 2)
+	}()
+	expectGoroutines(t, "dropped", before)
+}
@@ -0,0 +1,192 @@
+package go_param_table
+
+import (
+	"slices"
+	"unsafe"
+)
+
+const (
+	_PROP_DIRTY uint8 = 1 << iota
+	_PROP_CHANGED
+	_PROP_DONE
+	_PROP_STAGED
+	_PROP_CLAIMED
+)
+
+// Scratch state used by propagate(), allocated once by NewParamTable and reused on every update
+// so that propagation does not allocate once the lists have grown to their working size
+type propState[E Index] struct {
+	marks   []uint8
+	indeg   []uint32
+	changed []E
+	dirty   []E
+	queue   []E
+}
+
+func newPropState[E Index](paramCount uint32) propState[E] {
+	return propState[E]{
+		marks: make([]uint8, paramCount),
+		indeg: make([]uint32, paramCount),
+	}
+}
+
+func (p *propState[E]) memoryFootprint() uintptr {
+	size := uintptr(cap(p.marks))
+	size += uintptr(cap(p.indeg)) * 4
+	size += uintptr(cap(p.changed)+cap(p.dirty)+cap(p.queue)) * unsafe.Sizeof(E(0))
+	return size
+}
+
+// Records that the value at idx changed during the current update, so its dependants
+// will be re-evaluated by the next call to propagate()
+func (t *ParamTableOf[E]) markChanged(idx E) {
+	if t.prop.marks[idx]&_PROP_CHANGED == 0 {
+		t.prop.marks[idx] |= _PROP_CHANGED
+		t.prop.changed = append(t.prop.changed, idx)
+	}
+}
+
+func (t *ParamTableOf[E]) propagateFrom(idx E) {
+	t.markChanged(idx)
+	t.propagate()
+}
+
+// Calls fn for every value that may need re-evaluation when idx changes: the children of idx,
+// and the children of any sibling outputs that idx's calculation also writes
+func (t *ParamTableOf[E]) forEachSuccessor(idx E, fn func(child E)) {
+	for _, child := range t.getChildren(idx) {
+		fn(child)
+	}
+	for _, sib := range t.getSiblings(idx) {
+		if sib == idx {
+			continue
+		}
+		for _, child := range t.getChildren(sib) {
+			fn(child)
+		}
+	}
+}
+
+// Re-evaluates every derived value downstream of the values marked as changed, then notifies
+// the listeners of every changed value (see Subscribe())
+func (t *ParamTableOf[E]) propagate() {
+	t.evaluateChanged()
+	t.notify()
+}
+
+// Re-evaluates every derived value downstream of the values marked as changed.
+//
+// The set of dirty descendants is collected once, then walked in topological order
+// (Kahn's algorithm), so each derived value is evaluated at most once and only after
+// all of its dirty parents are final. A derived value is only evaluated if at least one
+// of its parents actually changed.
+func (t *ParamTableOf[E]) evaluateChanged() {
+	p := &t.prop
+	defer t.endPropagation()
+	// collect every value reachable from the changed values
+	p.queue = append(p.queue[:0], p.changed...)
+	for len(p.queue) > 0 {
+		idx := p.queue[len(p.queue)-1]
+		p.queue = p.queue[:len(p.queue)-1]
+		t.forEachSuccessor(idx, func(child E) {
+			if p.marks[child]&_PROP_DIRTY == 0 {
+				p.marks[child] |= _PROP_DIRTY
+				p.dirty = append(p.dirty, child)
+				p.queue = append(p.queue, child)
+			}
+		})
+	}
+	if len(p.dirty) == 0 {
+		t.queueChanged()
+		return
+	}
+	// count, for every dirty value, how many dirty values must be evaluated before it
+	for _, idx := range p.dirty {
+		t.forEachSuccessor(idx, func(child E) {
+			if p.marks[child]&_PROP_DIRTY != 0 {
+				p.indeg[child] += 1
+			}
+		})
+	}
+	for _, idx := range p.dirty {
+		if p.indeg[idx] == 0 {
+			p.queue = append(p.queue, idx)
+		}
+	}
+	if t.parallel != nil {
+		t.evaluateLevels()
+	} else {
+		for head := 0; head < len(p.queue); head += 1 {
+			idx := p.queue[head]
+			if p.marks[idx]&_PROP_DONE == 0 && t.anyParentChanged(idx) {
+				t.trigger(idx)
+				t.markSiblingsDone(idx)
+			}
+			t.forEachSuccessor(idx, func(child E) {
+				if p.marks[child]&_PROP_DIRTY != 0 {
+					p.indeg[child] -= 1
+					if p.indeg[child] == 0 {
+						p.queue = append(p.queue, child)
+					}
+				}
+			})
+		}
+	}
+	if len(p.queue) != len(p.dirty) && t.debug.enabled {
+		var cyclic []E
+		for _, idx := range p.dirty {
+			if p.indeg[idx] != 0 {
+				cyclic = append(cyclic, idx)
+			}
+		}
+		err := newParamError(ErrCycle, cyclic[0], "cyclic update loop: indexes [%s] depend on each other and can never be updated", t.formatIdxList(cyclic, ", "))
+		err.Path = widenIdxs(cyclic)
+		t.fail(err)
+	}
+	t.queueChanged()
+}
+
+// Clears all scratch marks left by the current update. Deferred by evaluateChanged() so the
+// table remains usable if a calculation panics part-way through
+func (t *ParamTableOf[E]) endPropagation() {
+	p := &t.prop
+	for _, idx := range p.dirty {
+		p.marks[idx] = 0
+		p.indeg[idx] = 0
+	}
+	for _, idx := range p.changed {
+		p.marks[idx] = 0
+	}
+	p.dirty = p.dirty[:0]
+	p.changed = p.changed[:0]
+	p.queue = p.queue[:0]
+}
+
+func (t *ParamTableOf[E]) anyParentChanged(idx E) bool {
+	for _, parent := range t.getParents(idx) {
+		if t.prop.marks[parent]&_PROP_CHANGED != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// A calculation with several outputs is normally registered once per output with the same
+// calc, inputs and outputs; once one of them has run, the others would only repeat the work
+func (t *ParamTableOf[E]) markSiblingsDone(idx E) {
+	for _, sib := range t.getSiblings(idx) {
+		if sib != idx && t.prop.marks[sib]&_PROP_DIRTY != 0 && t.sameCalc(idx, sib) {
+			t.prop.marks[sib] |= _PROP_DONE
+		}
+	}
+}
+
+func (t *ParamTableOf[E]) sameCalc(a E, b E) bool {
+	if !t.isDerived(a) || !t.isDerived(b) {
+		return false
+	}
+	if t.hookupData[uint32(t.hookups[a])+_HOOK_OFF_CALC] != t.hookupData[uint32(t.hookups[b])+_HOOK_OFF_CALC] {
+		return false
+	}
+	return slices.Equal(t.getParents(a), t.getParents(b)) && slices.Equal(t.getSiblings(a), t.getSiblings(b))
+}
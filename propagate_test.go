@@ -0,0 +1,225 @@
+package go_param_table
+
+import (
+	"testing"
+)
+
+// A port of the original depth-first update (set_*() -> updateChildren() -> trigger()), kept to
+// check propagate() against and to benchmark both. The original recursed from SetOutput_*() into
+// the children of an output before its calc returned; here the changed outputs are collected and
+// recursed into once the calc returns, which is the same order for the single output calcs of the
+// test graphs. Shared descendants are re-evaluated once per path. The cycle check the original ran
+// with debug checks enabled is left out, it reported every diamond as a cycle
+func (t *ParamTableOf[E]) setRootRecursive_F64(idx PIdx_F64, val float64) {
+	if setValue(t, uint32(idx), typeF64, val, false) {
+		var outs []E
+		t.updateChildrenRecursive(E(idx), &outs)
+	}
+}
+
+func (t *ParamTableOf[E]) updateChildrenRecursive(idx E, outs *[]E) {
+	for _, child := range t.getChildren(idx) {
+		t.triggerRecursive(child, outs)
+	}
+}
+
+// Runs the calc of idx and recurses into the children of each output it changed. outs is shared
+// by every level of the recursion, each level only uses the entries it appended
+func (t *ParamTableOf[E]) triggerRecursive(idx E, outs *[]E) {
+	start := len(*outs)
+	t.triggerInto(idx, outs)
+	for i := start; i < len(*outs); i += 1 {
+		t.updateChildrenRecursive((*outs)[i], outs)
+	}
+	*outs = (*outs)[:start]
+}
+
+const (
+	_TEST_CALC_ADD_ONE PIdx_Calc = iota
+	_TEST_CALC_DOUBLE
+	_TEST_CALC_SUM
+	_TEST_CALC_COUNT
+)
+
+type testGraph[E Index] struct {
+	table *ParamTableOf[E]
+	root  PIdx_F64
+	sink  PIdx_F64
+	count E
+	evals *int
+}
+
+// Creates a table of either width from a layout, for the tests that run against both
+func newTestTableOf[E Index](layout ParamLayout, calcsCount PIdx_Calc, opts ...TableOption) ParamTableOf[E] {
+	table, err := tryNewParamTableOf[E](layout.ends(), calcsCount, opts...)
+	if err != nil {
+		table.fail(err)
+	}
+	return table
+}
+
+// Creates an empty builder of either width, for the tests that run against both
+func newTestBuilderOf[E Index](opts ...TableOption) *TableBuilderOf[E] {
+	return &TableBuilderOf[E]{opts: opts}
+}
+
+func newF64TestTable(count uint16, opts ...TableOption) (*ParamTable, *int) {
+	return newF64TestTableOf[uint16](uint32(count), opts...)
+}
+
+// A float64-only table of either width with count parameters and the _TEST_CALC_* calcs
+func newF64TestTableOf[E Index](count uint32, opts ...TableOption) (*ParamTableOf[E], *int) {
+	table := newTestTableOf[E](ParamLayout{F64End: PIdx_F64(count)}, _TEST_CALC_COUNT, opts...)
+	evals := new(int)
+	table.RegisterCalc(_TEST_CALC_ADD_ONE, func(c *CalcInterfaceOf[E]) {
+		*evals += 1
+		c.SetOutput_F64(0, c.GetInput_F64(0)+1)
+	})
+	table.RegisterCalc(_TEST_CALC_DOUBLE, func(c *CalcInterfaceOf[E]) {
+		*evals += 1
+		c.SetOutput_F64(0, c.GetInput_F64(0)*2)
+	})
+	table.RegisterCalc(_TEST_CALC_SUM, func(c *CalcInterfaceOf[E]) {
+		*evals += 1
+		sum := 0.0
+		for i := range c.GetAllInputs() {
+			sum += c.GetInput_F64(uint16(i))
+		}
+		c.SetOutput_F64(0, sum)
+	})
+	return &table, evals
+}
+
+func derive_F64[E Index](table *ParamTableOf[E], idx E, calc PIdx_Calc, inputs ...E) {
+	table.InitDerived_F64(PIdx_F64(idx), false, calc, inputs, []E{idx})
+}
+
+// root -> 1 -> 2 -> ... -> length-1
+func newChainGraph[E Index](length E) testGraph[E] {
+	table, evals := newF64TestTableOf[E](uint32(length))
+	table.InitRoot_F64(0, 1, false)
+	for i := E(1); i < length; i += 1 {
+		derive_F64(table, i, _TEST_CALC_ADD_ONE, i-1)
+	}
+	return testGraph[E]{table: table, root: 0, sink: PIdx_F64(length - 1), count: length, evals: evals}
+}
+
+// root -> width independent values -> one sink summing all of them
+func newWideDiamondGraph[E Index](width E) testGraph[E] {
+	count := width + 2
+	table, evals := newF64TestTableOf[E](uint32(count))
+	table.InitRoot_F64(0, 1, false)
+	mids := make([]E, 0, width)
+	for i := E(1); i <= width; i += 1 {
+		if i%2 == 0 {
+			derive_F64(table, i, _TEST_CALC_ADD_ONE, 0)
+		} else {
+			derive_F64(table, i, _TEST_CALC_DOUBLE, 0)
+		}
+		mids = append(mids, i)
+	}
+	derive_F64(table, count-1, _TEST_CALC_SUM, mids...)
+	return testGraph[E]{table: table, root: 0, sink: PIdx_F64(count - 1), count: count, evals: evals}
+}
+
+// depth diamonds stacked on top of each other, each joining back into a single value,
+// which a depth-first recursive update re-evaluates 2^depth times
+func newStackedDiamondGraph[E Index](depth E) testGraph[E] {
+	count := 1 + (depth * 3)
+	table, evals := newF64TestTableOf[E](uint32(count))
+	table.InitRoot_F64(0, 1, false)
+	top := E(0)
+	for d := E(0); d < depth; d += 1 {
+		left, right, bottom := top+1, top+2, top+3
+		derive_F64(table, left, _TEST_CALC_ADD_ONE, top)
+		derive_F64(table, right, _TEST_CALC_DOUBLE, top)
+		derive_F64(table, bottom, _TEST_CALC_SUM, left, right)
+		top = bottom
+	}
+	return testGraph[E]{table: table, root: 0, sink: PIdx_F64(top), count: count, evals: evals}
+}
+
+func testGraphBuilders[E Index]() []struct {
+	name  string
+	build func() testGraph[E]
+} {
+	return []struct {
+		name  string
+		build func() testGraph[E]
+	}{
+		{"chain_1000", func() testGraph[E] { return newChainGraph[E](1000) }},
+		// deep enough for the stack growth of the recursive update to show
+		{"chain_60000", func() testGraph[E] { return newChainGraph[E](60000) }},
+		{"wide_diamond_200", func() testGraph[E] { return newWideDiamondGraph[E](200) }},
+		{"stacked_diamond_12", func() testGraph[E] { return newStackedDiamondGraph[E](12) }},
+	}
+}
+
+func TestPropagateMatchesRecursive(t *testing.T) {
+	t.Run("narrow", testPropagateMatchesRecursive[uint16])
+	t.Run("wide", testPropagateMatchesRecursive[uint32])
+}
+
+func testPropagateMatchesRecursive[E Index](t *testing.T) {
+	for _, builder := range testGraphBuilders[E]() {
+		iterative := builder.build()
+		recursive := builder.build()
+		iterative.table.Configure(WithDebug(true))
+		recursive.table.Configure(WithDebug(true))
+		for _, val := range []float64{2, 7.5, -3, -3, 1000} {
+			*iterative.evals = 0
+			iterative.table.SetRoot_F64(iterative.root, val)
+			recursive.table.setRootRecursive_F64(recursive.root, val)
+			for i := E(0); i < iterative.count; i += 1 {
+				got := iterative.table.Get_F64(PIdx_F64(i))
+				exp := recursive.table.Get_F64(PIdx_F64(i))
+				if got != exp {
+					t.Errorf("%s: root %f: idx %d:\n\tEXP: %f\n\tGOT: %f", builder.name, val, i, exp, got)
+				}
+			}
+			if *iterative.evals > int(iterative.count-1) {
+				t.Errorf("%s: root %f: %d calcs evaluated for %d derived values", builder.name, val, *iterative.evals, iterative.count-1)
+			}
+		}
+	}
+}
+
+func TestPropagateEvaluatesDiamondOnce(t *testing.T) {
+	t.Run("narrow", testPropagateEvaluatesDiamondOnce[uint16])
+	t.Run("wide", testPropagateEvaluatesDiamondOnce[uint32])
+}
+
+func testPropagateEvaluatesDiamondOnce[E Index](t *testing.T) {
+	g := newStackedDiamondGraph[E](4)
+	g.table.Configure(WithDebug(true))
+	*g.evals = 0
+	g.table.SetRoot_F64(g.root, 5)
+	if *g.evals != 12 {
+		t.Errorf("expected each of the 12 derived values to be evaluated exactly once, got %d evaluations", *g.evals)
+	}
+	*g.evals = 0
+	g.table.SetRoot_F64(g.root, 5)
+	if *g.evals != 0 {
+		t.Errorf("setting an unchanged root value evaluated %d calcs", *g.evals)
+	}
+}
+
+// Compares propagate() with the original recursive update on the same graphs
+func BenchmarkPropagate(b *testing.B) {
+	for _, builder := range testGraphBuilders[uint16]() {
+		b.Run(builder.name+"/iterative", func(b *testing.B) {
+			g := builder.build()
+			b.ResetTimer()
+			for i := 0; i < b.N; i += 1 {
+				g.table.SetRoot_F64(g.root, float64(i&1))
+			}
+		})
+		b.Run(builder.name+"/recursive", func(b *testing.B) {
+			g := builder.build()
+			b.ResetTimer()
+			for i := 0; i < b.N; i += 1 {
+				g.table.setRootRecursive_F64(g.root, float64(i&1))
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package go_param_table
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+type trackedObj struct {
+	val int
+	_   [4]int // keeps the object out of the tiny allocator, whose finalizers may never run
+}
+
+// Allocates an object whose only reference is returned as an unsafe.Pointer, freed is set once
+// the garbage collector has collected it
+func newTrackedObj(val int, freed *atomic.Bool) unsafe.Pointer {
+	obj := &trackedObj{val: val}
+	runtime.SetFinalizer(obj, func(*trackedObj) { freed.Store(true) })
+	return unsafe.Pointer(obj)
+}
+
+func collectGarbage() {
+	for i := 0; i < 5; i += 1 {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPointerParamsAreGCSafe(t *testing.T) {
+	t.Run("narrow", testPointerParamsAreGCSafe[uint16])
+	t.Run("wide", testPointerParamsAreGCSafe[uint32])
+}
+
+func testPointerParamsAreGCSafe[E Index](t *testing.T) {
+	const (
+		A PIdx_Ptr = iota
+		B
+		_PTR_PARAMS_END
+	)
+	const calcWrap PIdx_Calc = 0
+	table := newTestTableOf[E](ParamLayout{PtrEnd: _PTR_PARAMS_END}, 1, WithDebug(true))
+	var derivedFreed atomic.Bool
+	table.RegisterCalc(calcWrap, func(c *CalcInterfaceOf[E]) {
+		in := (*trackedObj)(c.GetInput_Ptr(0))
+		c.SetOutput_Ptr(0, newTrackedObj(in.val*10, &derivedFreed))
+	})
+	var expectVals = func(name string, a int, b int) {
+		t.Helper()
+		if got := (*trackedObj)(table.Get_Ptr(A)).val; got != a {
+			t.Errorf("%s: root:\n\tEXP: %d\n\tGOT: %d", name, a, got)
+		}
+		if got := (*trackedObj)(table.Get_Ptr(B)).val; got != b {
+			t.Errorf("%s: derived:\n\tEXP: %d\n\tGOT: %d", name, b, got)
+		}
+	}
+
+	var firstFreed, secondFreed atomic.Bool
+	first := newTrackedObj(1, &firstFreed)
+	table.InitRoot_Ptr(A, first, false)
+	table.InitDerived_Ptr(B, false, calcWrap, []E{E(A)}, []E{E(B)})
+	if table.Get_Ptr(A) != first {
+		t.Fatalf("Get_Ptr() did not return the stored pointer:\n\tEXP: %p\n\tGOT: %p", first, table.Get_Ptr(A))
+	}
+	first = nil
+	collectGarbage()
+	if firstFreed.Load() || derivedFreed.Load() {
+		t.Fatalf("objects only referenced by the table were collected (root %t, derived %t)", firstFreed.Load(), derivedFreed.Load())
+	}
+	expectVals("after GC", 1, 10)
+
+	// the batch log is the only holder of the old root until Rollback() restores it
+	table.BeginBatch()
+	table.SetRoot_Ptr(A, newTrackedObj(2, &secondFreed))
+	collectGarbage()
+	table.Rollback()
+	if firstFreed.Load() {
+		t.Fatalf("root value held by an open batch was collected")
+	}
+	collectGarbage()
+	expectVals("after rollback", 1, 10)
+	if !secondFreed.Load() {
+		t.Errorf("rolled back root value was never collected")
+	}
+
+	// replaced values are no longer referenced by the table
+	derivedFreed.Store(false)
+	table.SetRoot_Ptr(A, newTrackedObj(3, &secondFreed))
+	collectGarbage()
+	expectVals("after replacing root", 3, 30)
+	if !firstFreed.Load() || !derivedFreed.Load() {
+		t.Errorf("replaced values were never collected (root %t, derived %t)", firstFreed.Load(), derivedFreed.Load())
+	}
+	if size := table.TotalMemoryFootprint(); size < uintptr(_PTR_PARAMS_END)*unsafe.Sizeof(unsafe.Pointer(nil)) {
+		t.Errorf("pointer storage missing from memory footprint (%d bytes)", size)
+	}
+}
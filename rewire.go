@@ -0,0 +1,193 @@
+package go_param_table
+
+import (
+	"slices"
+	"unsafe"
+)
+
+// The functions below change the structure of an already initialized table. Unlike the
+// Init/Get/Set functions they always perform the full safety checks, since a mistake would
+// leave hookupData inconsistent and they are not meant to be used on a hot path
+
+// An option passed to Rewire()
+type RewireOption func(r *rewireConfig)
+
+type rewireConfig struct {
+	noRecalc bool
+}
+
+// Keeps the current value of the rewired value instead of recalculating it (and everything
+// derived from it) right away. It is next recalculated when one of its new inputs changes
+func WithoutRecalc() RewireOption {
+	return func(r *rewireConfig) {
+		r.noRecalc = true
+	}
+}
+
+// Detaches a derived value from its parents and un-initializes it, its value is reset to zero.
+// If it is one of several outputs of a calc, the other outputs registered with the same calc,
+// inputs and outputs are removed with it. Panics if it is not derived, if other derived values
+// are still calculated from it or from the other outputs, or if it is also written by an
+// unrelated calc (see TryRemoveDerived())
+func (t *ParamTableOf[E]) RemoveDerived(idx E) {
+	if err := t.TryRemoveDerived(idx); err != nil {
+		t.fail(err)
+	}
+}
+
+// Same as RemoveDerived(), but returns an error instead of panicking
+func (t *ParamTableOf[E]) TryRemoveDerived(idx E) error {
+	if err := t.derivedErr("RemoveDerived", idx); err != nil {
+		return err
+	}
+	group, err := t.outputGroup("RemoveDerived", idx, nil)
+	if err != nil {
+		return err
+	}
+	for _, member := range group {
+		if t.hasChildren(member) {
+			return newParamError(ErrHasChildren, member, "RemoveDerived(): index %s still has children %s, remove or rewire them first", t.paramLabel(uint32(member)), t.formatIdxList(t.getChildren(member), ", "))
+		}
+	}
+	t.structure = nextStructureID()
+	for _, member := range group {
+		t.detachFromParents(member)
+		t.deleteSegment(member)
+		clearFlag(uint32(member), t.flags, _PFLAG_INIT|_PFLAG_ALWAYS_UPDATE)
+		typeIdx := t.typeOfIdx(uint32(member))
+		memPtr, subIdx := t.getBytePtr(uint32(member), typeIdx)
+		switch typeIdx {
+		case typePtr:
+			t.ptrs[subIdx] = nil
+		case typeStr:
+			t.strs[subIdx] = ""
+		case typeVal:
+			t.vals[subIdx] = nil
+			t.valEqual[subIdx] = nil
+		default:
+			clear(unsafe.Slice(memPtr, sizeTable[typeIdx]))
+		}
+	}
+	return nil
+}
+
+// Detaches a derived value from its parents and turns it into a root value that keeps its
+// current value and children. If it is one of several outputs of a calc, the other outputs
+// registered with the same calc, inputs and outputs are demoted with it. Panics if it is not
+// derived, or if it is also written by an unrelated calc (see TryDemoteToRoot())
+func (t *ParamTableOf[E]) DemoteToRoot(idx E) {
+	if err := t.TryDemoteToRoot(idx); err != nil {
+		t.fail(err)
+	}
+}
+
+// Same as DemoteToRoot(), but returns an error instead of panicking
+func (t *ParamTableOf[E]) TryDemoteToRoot(idx E) error {
+	if err := t.derivedErr("DemoteToRoot", idx); err != nil {
+		return err
+	}
+	group, err := t.outputGroup("DemoteToRoot", idx, nil)
+	if err != nil {
+		return err
+	}
+	t.structure = nextStructureID()
+	for _, member := range group {
+		t.detachFromParents(member)
+		children := t.getChildren(member)
+		if len(children) == 0 {
+			t.deleteSegment(member)
+			continue
+		}
+		t.replaceSegment(member, newSegment(0, nil, nil, children))
+	}
+	return nil
+}
+
+// Replaces the calc, inputs and outputs of an initialized value (turning a root value into a
+// derived one if needed), keeping its children, then recalculates it and everything derived
+// from it (unless WithoutRecalc() is passed). If it is one of several outputs of a calc, the
+// other outputs registered with the same calc, inputs and outputs must be listed in outputs as
+// well, and are rewired with it. Panics if the new hookup is invalid (see TryRewire())
+func (t *ParamTableOf[E]) Rewire(idx E, calcIdx PIdx_Calc, inputs []E, outputs []E, opts ...RewireOption) {
+	if err := t.TryRewire(idx, calcIdx, inputs, outputs, opts...); err != nil {
+		t.fail(err)
+	}
+}
+
+// Same as Rewire(), but returns an error instead of panicking. The table is not modified if an
+// error is returned
+func (t *ParamTableOf[E]) TryRewire(idx E, calcIdx PIdx_Calc, inputs []E, outputs []E, opts ...RewireOption) error {
+	var config rewireConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if err := t.initErr(uint32(idx)); err != nil {
+		return err
+	}
+	group, err := t.outputGroup("Rewire", idx, func(other E) bool {
+		return t.hasHookup(other, calcIdx, inputs, outputs)
+	})
+	if err != nil {
+		return err
+	}
+	for _, member := range group {
+		if member != idx && !slices.Contains(outputs, member) {
+			return newParamError(ErrSharedOutput, idx, "Rewire(): index %s is calculated together with %s, list both in outputs or call DemoteToRoot() first", t.paramLabel(uint32(idx)), t.paramLabel(uint32(member)))
+		}
+		if err := t.hookupErr(member, calcIdx, inputs, outputs, true); err != nil {
+			return err
+		}
+	}
+	t.structure = nextStructureID()
+	for _, member := range group {
+		t.detachFromParents(member)
+		children := t.getChildren(member)
+		t.replaceSegment(member, newSegment(calcIdx, inputs, outputs, children))
+		for _, parent := range inputs {
+			t.addChild(parent, member)
+		}
+	}
+	if !config.noRecalc {
+		t.trigger(idx)
+		t.propagateFrom(idx)
+	}
+	return nil
+}
+
+// Returns the values a structural change of idx applies to: idx, and every other derived value
+// registered with the same calc, inputs and outputs that lists idx as an output (the other
+// outputs of a calc with several outputs). The error is an ErrSharedOutput error if idx is an
+// output of the calc of any other derived value, unless keeps reports that this value may keep
+// writing it
+func (t *ParamTableOf[E]) outputGroup(funcName string, idx E, keeps func(other E) bool) ([]E, error) {
+	group := []E{idx}
+	for i := range t.hookups {
+		other := E(i)
+		if other == idx || !t.isDerived(other) || !slices.Contains(t.getSiblings(other), idx) {
+			continue
+		}
+		if keeps != nil && keeps(other) {
+			continue
+		}
+		if !t.sameCalc(idx, other) {
+			return nil, newParamError(ErrSharedOutput, idx, "%s(): index %s is also an output of the calc of %s, rewire or remove it first", funcName, t.paramLabel(uint32(idx)), t.paramLabel(uint32(other)))
+		}
+		group = append(group, other)
+	}
+	return group, nil
+}
+
+// Whether the derived value idx is calculated by calcIdx from inputs into outputs
+func (t *ParamTableOf[E]) hasHookup(idx E, calcIdx PIdx_Calc, inputs []E, outputs []E) bool {
+	return t.hookupData[uint32(t.hookups[idx])+_HOOK_OFF_CALC] == E(calcIdx) && slices.Equal(t.getParents(idx), inputs) && slices.Equal(t.getSiblings(idx), outputs)
+}
+
+func (t *ParamTableOf[E]) derivedErr(funcName string, idx E) error {
+	if err := t.initErr(uint32(idx)); err != nil {
+		return err
+	}
+	if !t.isDerived(idx) {
+		return newParamError(ErrNotDerived, idx, "%s(): index %s is not a derived value", funcName, t.paramLabel(uint32(idx)))
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package go_param_table
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Checks that every parent -> child edge is recorded on both ends
+func checkHookupConsistency[E Index](t *testing.T, table *ParamTableOf[E]) {
+	t.Helper()
+	for i := range table.hookups {
+		idx := E(i)
+		for _, parent := range table.getParents(idx) {
+			if !slices.Contains(table.getChildren(parent), idx) {
+				t.Errorf("index %d is missing from the children of its parent %d", idx, parent)
+			}
+		}
+		for _, child := range table.getChildren(idx) {
+			if !slices.Contains(table.getParents(child), idx) {
+				t.Errorf("index %d lists %d as a child, but is not one of its parents", idx, child)
+			}
+		}
+	}
+}
+
+func TestRewire(t *testing.T) {
+	t.Run("narrow", testRewire[uint16])
+	t.Run("wide", testRewire[uint32])
+}
+
+func testRewire[E Index](t *testing.T) {
+	const (
+		A PIdx_F64 = iota
+		B
+		C
+		D
+		_F64_PARAMS_END
+	)
+	table, _ := newF64TestTableOf[E](uint32(_F64_PARAMS_END), WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(A, 1, false)
+	derive_F64(table, E(B), _TEST_CALC_ADD_ONE, E(A))
+	derive_F64(table, E(C), _TEST_CALC_DOUBLE, E(B))
+	derive_F64(table, E(D), _TEST_CALC_ADD_ONE, E(B))
+
+	var expectVals = func(name string, exp ...float64) {
+		t.Helper()
+		for i, val := range exp {
+			if got := table.Get_F64(PIdx_F64(i)); got != val {
+				t.Errorf("%s: idx %d:\n\tEXP: %f\n\tGOT: %f", name, i, val, got)
+			}
+		}
+		checkHookupConsistency(t, table)
+	}
+	var expectErr = func(name string, err error, kind error) {
+		t.Helper()
+		if !errors.Is(err, kind) {
+			t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", name, kind, err)
+		}
+	}
+	expectVals("initial", 1, 2, 4, 3)
+
+	expectErr("remove with children", table.TryRemoveDerived(E(B)), ErrHasChildren)
+	expectErr("remove root", table.TryRemoveDerived(E(A)), ErrNotDerived)
+	expectErr("demote root", table.TryDemoteToRoot(E(A)), ErrNotDerived)
+	expectErr("rewire into cycle", table.TryRewire(E(B), _TEST_CALC_ADD_ONE, []E{E(D)}, []E{E(B)}), ErrCycle)
+	expectVals("after rejected changes", 1, 2, 4, 3)
+
+	// same segment length, so it is rewritten in place
+	table.Rewire(E(B), _TEST_CALC_DOUBLE, []E{E(A)}, []E{E(B)})
+	expectVals("rewire calc", 1, 2, 4, 3)
+	table.SetRoot_F64(A, 3)
+	expectVals("rewire calc then set", 3, 6, 12, 7)
+
+	// C moves from B to (A, D), so B loses a child that is not its last one
+	table.Rewire(E(C), _TEST_CALC_SUM, []E{E(A), E(D)}, []E{E(C)})
+	expectVals("rewire inputs", 3, 6, 10, 7)
+	if children := table.Children(E(B)); !slices.Equal(children, []E{E(D)}) {
+		t.Errorf("children of B:\n\tEXP: %v\n\tGOT: %v", []E{E(D)}, children)
+	}
+
+	table.DemoteToRoot(E(B))
+	if !table.IsRoot(E(B)) || slices.Contains(table.Children(E(A)), E(B)) {
+		t.Errorf("demoted value is still derived")
+	}
+	expectVals("demote", 3, 6, 10, 7)
+	table.SetRoot_F64(B, 10)
+	expectVals("set demoted", 3, 10, 14, 11)
+	table.SetRoot_F64(A, 1)
+	expectVals("set old parent of demoted", 1, 10, 12, 11)
+
+	table.RemoveDerived(E(C))
+	table.RemoveDerived(E(D))
+	if table.IsInit(E(D)) || table.HasChildren(E(A)) || table.HasChildren(E(B)) {
+		t.Errorf("removed values are still hooked up")
+	}
+	_, err := table.TryGet_F64(D)
+	expectErr("get removed", err, ErrNotInitialized)
+	checkHookupConsistency(t, table)
+
+	// a removed value can be initialized again, and a root can be rewired into a derived value
+	derive_F64(table, E(C), _TEST_CALC_DOUBLE, E(B))
+	derive_F64(table, E(D), _TEST_CALC_ADD_ONE, E(B))
+	table.Rewire(E(A), _TEST_CALC_DOUBLE, []E{E(D)}, []E{E(A)})
+	expectVals("reinit and rewire root", 22, 10, 20, 11)
+	table.SetRoot_F64(B, 1)
+	expectVals("set after rewiring root", 4, 1, 2, 2)
+}
+
+func TestRewireMultipleOutputs(t *testing.T) {
+	t.Run("narrow", testRewireMultipleOutputs[uint16])
+	t.Run("wide", testRewireMultipleOutputs[uint32])
+}
+
+func testRewireMultipleOutputs[E Index](t *testing.T) {
+	b := newTestBuilderOf[E](WithDebug(true), WithVerbosity(VerbositySilent))
+	aPtr, bPtr, cPtr, loPtr, hiPtr, widthPtr := b.F64("a"), b.F64("b"), b.F64("c"), b.F64("lo"), b.F64("hi"), b.F64("width")
+	calcRange := b.Calc("range", func(c *CalcInterfaceOf[E]) {
+		c.SetOutput_F64(0, min(c.GetInput_F64(0), c.GetInput_F64(1)))
+		c.SetOutput_F64(1, max(c.GetInput_F64(0), c.GetInput_F64(1)))
+	})
+	calcWidth := b.Calc("width", func(c *CalcInterfaceOf[E]) {
+		c.SetOutput_F64(0, c.GetInput_F64(1)-c.GetInput_F64(0))
+	})
+	table := b.Build()
+	A, B, C, Lo, Hi, Width := *aPtr, *bPtr, *cPtr, *loPtr, *hiPtr, *widthPtr
+	idxs := func(idxs ...PIdx_F64) (list []E) {
+		for _, idx := range idxs {
+			list = append(list, E(idx))
+		}
+		return
+	}
+	table.InitRoot_F64(A, 1, false)
+	table.InitRoot_F64(B, 5, false)
+	table.InitRoot_F64(C, 10, false)
+	table.InitDerived_F64(Lo, false, *calcRange, idxs(A, B), idxs(Lo, Hi))
+	table.InitDerived_F64(Hi, false, *calcRange, idxs(A, B), idxs(Lo, Hi))
+	table.InitDerived_F64(Width, false, *calcWidth, idxs(Lo, Hi), idxs(Width))
+
+	var expectVals = func(name string, lo, hi float64) {
+		t.Helper()
+		if table.Get_F64(Lo) != lo || table.Get_F64(Hi) != hi || table.Get_F64(Width) != hi-lo {
+			t.Errorf("%s:\n\tEXP: %f, %f, %f\n\tGOT: %f, %f, %f", name, lo, hi, hi-lo, table.Get_F64(Lo), table.Get_F64(Hi), table.Get_F64(Width))
+		}
+		checkHookupConsistency(t, &table)
+	}
+	var expectErr = func(name string, err error, kind error) {
+		t.Helper()
+		if !errors.Is(err, kind) {
+			t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", name, kind, err)
+		}
+	}
+	expectVals("initial", 1, 5)
+	expectErr("rewire one output", table.TryRewire(E(Lo), *calcRange, idxs(A, C), idxs(Lo)), ErrSharedOutput)
+	expectErr("remove output with children", table.TryRemoveDerived(E(Hi)), ErrHasChildren)
+	expectVals("after rejected changes", 1, 5)
+
+	// every output of the calc is rewired at once
+	table.Rewire(E(Hi), *calcRange, idxs(A, C), idxs(Lo, Hi))
+	expectVals("rewire", 1, 10)
+	if !slices.Equal(table.Parents(E(Lo)), idxs(A, C)) || table.HasChildren(E(B)) {
+		t.Errorf("other output not rewired: parents %v", table.Parents(E(Lo)))
+	}
+	table.SetRoot_F64(B, 20)
+	expectVals("set old input", 1, 10)
+
+	table.Rewire(E(Lo), *calcRange, idxs(B, C), idxs(Lo, Hi), WithoutRecalc())
+	expectVals("rewire without recalc", 1, 10)
+	table.SetRoot_F64(C, 3)
+	expectVals("set after rewire without recalc", 3, 20)
+
+	table.DemoteToRoot(E(Lo))
+	if !table.IsRoot(E(Lo)) || !table.IsRoot(E(Hi)) {
+		t.Errorf("other output not demoted")
+	}
+	table.SetRoot_F64(Hi, 7)
+	table.SetRoot_F64(C, 100)
+	expectVals("demote", 3, 7)
+
+	// roots are rewired into outputs of the same calc one at a time
+	table.Rewire(E(Lo), *calcRange, idxs(A, B), idxs(Lo, Hi))
+	table.Rewire(E(Hi), *calcRange, idxs(A, B), idxs(Lo, Hi))
+	expectVals("rewire roots", 1, 20)
+	table.RemoveDerived(E(Width))
+	table.RemoveDerived(E(Lo))
+	if table.IsInit(E(Lo)) || table.IsInit(E(Hi)) || table.HasChildren(E(A)) {
+		t.Errorf("other output not removed")
+	}
+	checkHookupConsistency(t, &table)
+
+	// a value written by an unrelated calc cannot be changed
+	table.InitRoot_F64(Lo, 0, false)
+	table.InitDerived_F64(Hi, false, *calcRange, idxs(A, B), idxs(Lo, Hi))
+	expectErr("rewire output of another calc", table.TryRewire(E(Lo), *calcWidth, idxs(A, B), idxs(Lo)), ErrSharedOutput)
+}
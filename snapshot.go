@@ -0,0 +1,133 @@
+package go_param_table
+
+import (
+	"maps"
+	"slices"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Every structural change (a new derived value, RemoveDerived(), Rewire(), ...) gives the table a
+// new structure id, taken from this process-wide counter so that no two unrelated tables share one
+var structureIDs atomic.Uint64
+
+func nextStructureID() uint64 {
+	return structureIDs.Add(1)
+}
+
+// An immutable copy of the value state of a table: every value, the initialized and
+// alwaysUpdate flags, and the equality funcs of opaque values. It does not include the
+// dependency graph, registered calcs or metadata, see Snapshot()
+type Snapshot struct {
+	values     []byte
+	ptrs       []unsafe.Pointer
+	strs       []string
+	vals       []any
+	valEqual   []func(a, b any) bool
+	flags      []paramFlags
+	idxOffsets [typeCount]uint32
+	paramCount int
+	structure  uint64
+}
+
+// Returns the memory taken up by the snapshot. As with TotalMemoryFootprint(), only the interface
+// headers of opaque values are counted
+func (s *Snapshot) MemoryFootprint() uintptr {
+	size := unsafe.Sizeof(*s)
+	size += uintptr(cap(s.values))
+	size += uintptr(cap(s.ptrs)) * uintptr(sizePtr)
+	size += uintptr(cap(s.strs)) * uintptr(sizeStr)
+	for _, str := range s.strs {
+		size += uintptr(len(str))
+	}
+	size += uintptr(cap(s.vals)) * uintptr(sizeVal)
+	size += uintptr(cap(s.valEqual)) * unsafe.Sizeof((func(a, b any) bool)(nil))
+	size += uintptr(cap(s.flags)) * unsafe.Sizeof(paramFlags(0))
+	return size
+}
+
+// Copies the current value state of the table. The snapshot can later be passed to Restore() on
+// this table, or on a Clone() of it, for as long as the dependency graph of that table is not
+// changed (by InitDerived_*(), RemoveDerived(), DemoteToRoot() or Rewire()).
+//
+// Pointer and opaque values are copied as-is, so the snapshot keeps the objects they reference
+// alive but shares them with the table
+func (t *ParamTableOf[E]) Snapshot() *Snapshot {
+	return &Snapshot{
+		values:     slices.Clone(t.values),
+		ptrs:       slices.Clone(t.ptrs),
+		strs:       slices.Clone(t.strs),
+		vals:       slices.Clone(t.vals),
+		valEqual:   slices.Clone(t.valEqual),
+		flags:      slices.Clone(t.flags),
+		idxOffsets: t.idxOffsets,
+		paramCount: len(t.hookups),
+		structure:  t.structure,
+	}
+}
+
+// Reinstates the value state of a snapshot, derived values included, without running any calc,
+// then notifies the listeners of every value it changed. Without listeners it only copies into
+// the existing storage of the table, so it does not allocate and is cheap enough to call every frame. Panics if the snapshot does not match the table (see TryRestore())
+func (t *ParamTableOf[E]) Restore(snap *Snapshot) {
+	if err := t.TryRestore(snap); err != nil {
+		t.fail(err)
+	}
+}
+
+// Same as Restore(), but returns an error instead of panicking: ErrLayout if the snapshot was
+// taken from a table with different type regions, or ErrStaleSnapshot if the dependency graph
+// changed since it was taken. The table is not modified if an error is returned
+func (t *ParamTableOf[E]) TryRestore(snap *Snapshot) error {
+	null := ^E(0)
+	if snap.idxOffsets != t.idxOffsets || snap.paramCount != len(t.hookups) {
+		return newParamError(ErrLayout, null, "Restore(): snapshot was taken from a table with a different layout")
+	}
+	if snap.structure != t.structure {
+		return newParamError(ErrStaleSnapshot, null, "Restore(): the dependency graph changed since the snapshot was taken")
+	}
+	copy(t.values, snap.values)
+	copy(t.ptrs, snap.ptrs)
+	copy(t.strs, snap.strs)
+	copy(t.vals, snap.vals)
+	copy(t.valEqual, snap.valEqual)
+	copy(t.flags, snap.flags)
+	t.notifyAll()
+	return nil
+}
+
+// Returns an independent copy of the table, sharing no mutable memory with it: values, the
+// dependency graph, metadata, the undo history and any open batch are all copied. Registered
+// calcs are shared, so calcs that capture state of their own see the same state from both tables.
+// Listeners (see Subscribe()) and the front buffer (see EnableFrontBuffer()) are not copied
+func (t *ParamTableOf[E]) Clone() ParamTableOf[E] {
+	clone := *t
+	clone.values = slices.Clone(t.values)
+	clone.ptrs = slices.Clone(t.ptrs)
+	clone.strs = slices.Clone(t.strs)
+	clone.vals = slices.Clone(t.vals)
+	clone.valEqual = slices.Clone(t.valEqual)
+	clone.flags = slices.Clone(t.flags)
+	clone.hookups = slices.Clone(t.hookups)
+	clone.hookupData = slices.Clone(t.hookupData)
+	clone.calcs = slices.Clone(t.calcs)
+	clone.prop = newPropState[E](uint32(len(t.hookups)))
+	clone.batch = batchState{
+		log:    slices.Clone(t.batch.log),
+		starts: slices.Clone(t.batch.starts),
+	}
+	clone.history = t.history.clone()
+	clone.subs = nil
+	clone.front = nil
+	clone.parallelCalcs = slices.Clone(t.parallelCalcs)
+	if t.parallel != nil {
+		// the clone is returned by value, so its helpers are started on its first parallel update
+		clone.parallel = &parallelState[E]{workers: t.parallel.workers}
+	}
+	clone.meta = metaRegistry[E]{
+		params:    slices.Clone(t.meta.params),
+		byName:    maps.Clone(t.meta.byName),
+		calcNames: slices.Clone(t.meta.calcNames),
+	}
+	return clone
+}
@@ -0,0 +1,152 @@
+package go_param_table
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Run("narrow", testSnapshotRestore[uint16])
+	t.Run("wide", testSnapshotRestore[uint32])
+}
+
+func testSnapshotRestore[E Index](t *testing.T) {
+	// 0 -> 1 -> 2, with 3 left free
+	table, evals := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 1, false)
+	derive_F64(table, 1, _TEST_CALC_ADD_ONE, 0)
+	derive_F64(table, 2, _TEST_CALC_DOUBLE, 1)
+	var expectVals = func(name string, exp ...float64) {
+		t.Helper()
+		for i, val := range exp {
+			if got := table.Get_F64(PIdx_F64(i)); got != val {
+				t.Errorf("%s: idx %d:\n\tEXP: %f\n\tGOT: %f", name, i, val, got)
+			}
+		}
+	}
+	snap := table.Snapshot()
+
+	table.SetRoot_F64(0, 10)
+	table.InitRoot_F64(3, 7, false)
+	expectVals("modified", 10, 11, 22, 7)
+	*evals = 0
+	table.Restore(snap)
+	expectVals("restored", 1, 2, 4)
+	if *evals != 0 {
+		t.Errorf("restore evaluated %d calcs", *evals)
+	}
+	if table.IsInit(3) {
+		t.Errorf("value initialized after the snapshot is still initialized")
+	}
+	table.SetRoot_F64(0, 2)
+	expectVals("set after restore", 2, 3, 6)
+
+	// the snapshot is not affected by later changes and can be restored again
+	table.Restore(snap)
+	expectVals("restored twice", 1, 2, 4)
+	if allocs := testing.AllocsPerRun(10, func() { table.Restore(snap) }); allocs != 0 {
+		t.Errorf("restore allocated %.0f times", allocs)
+	}
+
+	table.InitRoot_F64(3, 1, false)
+	derive_F64(table, 3, _TEST_CALC_ADD_ONE, 2)
+	if err := table.TryRestore(snap); !errors.Is(err, ErrStaleSnapshot) {
+		t.Errorf("restore after a graph change:\n\tEXP: %v\n\tGOT: %v", ErrStaleSnapshot, err)
+	}
+	other, _ := newF64TestTableOf[E](5)
+	if err := other.TryRestore(snap); !errors.Is(err, ErrLayout) {
+		t.Errorf("restore into another layout:\n\tEXP: %v\n\tGOT: %v", ErrLayout, err)
+	}
+	same, _ := newF64TestTableOf[E](4)
+	if err := same.TryRestore(snap); !errors.Is(err, ErrStaleSnapshot) {
+		t.Errorf("restore into an unrelated table:\n\tEXP: %v\n\tGOT: %v", ErrStaleSnapshot, err)
+	}
+}
+
+func TestSnapshotSideStores(t *testing.T) {
+	t.Run("narrow", testSnapshotSideStores[uint16])
+	t.Run("wide", testSnapshotSideStores[uint32])
+}
+
+func testSnapshotSideStores[E Index](t *testing.T) {
+	const (
+		Name PIdx_Str = iota
+		_STR_PARAMS_END
+	)
+	const (
+		Tags            PIdx_Val[[]string] = PIdx_Val[[]string](iota + _STR_PARAMS_END)
+		_VAL_PARAMS_END PIdx_Val[any]      = PIdx_Val[any](iota + _STR_PARAMS_END)
+	)
+	const _end = uint16(_VAL_PARAMS_END)
+	table := newTestTableOf[E](ParamLayout{StrEnd: _STR_PARAMS_END, ValEnd: _VAL_PARAMS_END}, 0,
+		WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_Str(Name, "before", false)
+	InitRootVal(&table, Tags, []string{"a"}, false, nil)
+	snap := table.Snapshot()
+	table.SetRoot_Str(Name, "after")
+	SetVal(&table, Tags, []string{"b", "c"})
+	table.Restore(snap)
+	if got := table.Get_Str(Name); got != "before" {
+		t.Errorf("string:\n\tEXP: %q\n\tGOT: %q", "before", got)
+	}
+	if got := GetVal(&table, Tags); len(got) != 1 || got[0] != "a" {
+		t.Errorf("opaque value:\n\tEXP: [a]\n\tGOT: %v", got)
+	}
+	if snap.MemoryFootprint() < uintptr(len("before")) {
+		t.Errorf("snapshot memory footprint too small: %d", snap.MemoryFootprint())
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Run("narrow", testClone[uint16])
+	t.Run("wide", testClone[uint32])
+}
+
+func testClone[E Index](t *testing.T) {
+	table, _ := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 1, false)
+	derive_F64(table, 1, _TEST_CALC_ADD_ONE, 0)
+	table.SetMeta(1, ParamMeta{Name: "one"})
+	snap := table.Snapshot()
+
+	clone := table.Clone()
+	clone.SetRoot_F64(0, 5)
+	clone.InitRoot_F64(2, 3, false)
+	derive_F64(&clone, 3, _TEST_CALC_SUM, 1, 2)
+	clone.SetMeta(1, ParamMeta{Name: "renamed"})
+	if got := clone.Get_F64(3); got != 9 {
+		t.Errorf("clone:\n\tEXP: %f\n\tGOT: %f", 9.0, got)
+	}
+	if table.Get_F64(1) != 2 || table.IsInit(2) || table.HasChildren(1) || table.Name(1) != "one" {
+		t.Errorf("changes to the clone leaked into the original: %f, %t, %t, %q", table.Get_F64(1), table.IsInit(2), table.HasChildren(1), table.Name(1))
+	}
+	table.SetRoot_F64(0, 100)
+	if got := clone.Get_F64(1); got != 6 {
+		t.Errorf("changes to the original leaked into the clone:\n\tEXP: %f\n\tGOT: %f", 6.0, got)
+	}
+	checkHookupConsistency(t, &clone)
+
+	// a snapshot of the original applies to a clone until its graph changes
+	unchanged := table.Clone()
+	unchanged.Restore(snap)
+	if got := unchanged.Get_F64(1); got != 2 {
+		t.Errorf("snapshot restored into clone:\n\tEXP: %f\n\tGOT: %f", 2.0, got)
+	}
+	if err := clone.TryRestore(snap); !errors.Is(err, ErrStaleSnapshot) {
+		t.Errorf("snapshot restored into changed clone:\n\tEXP: %v\n\tGOT: %v", ErrStaleSnapshot, err)
+	}
+}
+
+func BenchmarkRestore(b *testing.B) {
+	for _, count := range []uint16{1000, 60000} {
+		b.Run(fmt.Sprintf("params_%d", count), func(b *testing.B) {
+			g := newChainGraph[uint16](count)
+			snap := g.table.Snapshot()
+			b.ResetTimer()
+			for i := 0; i < b.N; i += 1 {
+				g.table.Restore(snap)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package go_param_table
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStringParams(t *testing.T) {
+	t.Run("narrow", testStringParams[uint16])
+	t.Run("wide", testStringParams[uint32])
+}
+
+func testStringParams[E Index](t *testing.T) {
+	const (
+		Width PIdx_F64 = iota
+		_F64_PARAMS_END
+	)
+	const (
+		Unit PIdx_Str = PIdx_Str(iota + _F64_PARAMS_END)
+		Label
+		Title
+		_STR_PARAMS_END
+	)
+	const (
+		calcLabel PIdx_Calc = iota
+		calcTitle
+		_CALC_COUNT
+	)
+	table := newTestTableOf[E](ParamLayout{F64End: _F64_PARAMS_END, StrEnd: _STR_PARAMS_END}, _CALC_COUNT,
+		WithDebug(true), WithVerbosity(VerbositySilent))
+	titleEvals := 0
+	table.RegisterCalc(calcLabel, func(c *CalcInterfaceOf[E]) {
+		c.SetOutput_Str(0, fmt.Sprintf("%.1f %s", c.GetInput_F64(0), c.GetInput_Str(1)))
+	})
+	table.RegisterCalc(calcTitle, func(c *CalcInterfaceOf[E]) {
+		titleEvals += 1
+		c.SetOutput_Str(0, strings.ToUpper(c.GetInput_Str(0)))
+	})
+	table.InitRoot_F64(Width, 12, false)
+	table.InitRoot_Str(Unit, "px", false)
+	table.InitDerived_Str(Label, false, calcLabel, []E{E(Width), E(Unit)}, []E{E(Label)})
+	table.InitDerived_Str(Title, false, calcTitle, []E{E(Label)}, []E{E(Title)})
+
+	var expectVals = func(name string, label string, title string) {
+		t.Helper()
+		if got := table.Get_Str(Label); got != label {
+			t.Errorf("%s: label:\n\tEXP: %q\n\tGOT: %q", name, label, got)
+		}
+		if got := table.Get_Str(Title); got != title {
+			t.Errorf("%s: title:\n\tEXP: %q\n\tGOT: %q", name, title, got)
+		}
+	}
+	expectVals("initial", "12.0 px", "12.0 PX")
+
+	table.SetRoot_Str(Unit, "em")
+	expectVals("set string root", "12.0 em", "12.0 EM")
+
+	// a new string with equal contents is not a change
+	titleEvals = 0
+	table.SetRoot_Str(Unit, strings.Clone("em"))
+	table.SetRoot_F64(Width, 12.04)
+	if titleEvals != 0 {
+		t.Errorf("equal strings triggered %d evaluations", titleEvals)
+	}
+	expectVals("equal strings", "12.0 em", "12.0 EM")
+
+	before := table.TotalMemoryFootprint()
+	table.SetRoot_Str(Unit, strings.Repeat("m", 1000))
+	if after := table.TotalMemoryFootprint(); after < before+2000 {
+		t.Errorf("string contents missing from memory footprint: %d -> %d", before, after)
+	}
+
+	table.BeginBatch()
+	table.SetRoot_Str(Unit, "pt")
+	table.SetRoot_F64(Width, 3)
+	table.CommitBatch()
+	expectVals("batch", "3.0 pt", "3.0 PT")
+	table.BeginBatch()
+	table.SetRoot_Str(Unit, "cm")
+	table.Rollback()
+	if got := table.Get_Str(Unit); got != "pt" {
+		t.Errorf("rollback:\n\tEXP: %q\n\tGOT: %q", "pt", got)
+	}
+
+	// contents built at runtime only live in the table
+	var sb strings.Builder
+	sb.WriteString("dynamic ")
+	sb.WriteString(strings.Repeat("x", 64))
+	table.SetRoot_Str(Unit, sb.String())
+	sb.Reset()
+	collectGarbage()
+	expectVals("after GC", "3.0 dynamic "+strings.Repeat("x", 64), "3.0 DYNAMIC "+strings.Repeat("X", 64))
+
+	if _, err := table.TryGet_Str(PIdx_Str(Width)); !errors.Is(err, ErrWrongType) {
+		t.Errorf("get string from float index:\n\tEXP: %v\n\tGOT: %v", ErrWrongType, err)
+	}
+	if err := table.TrySetRoot_Str(Label, "x"); !errors.Is(err, ErrDerivedNotSettable) {
+		t.Errorf("set derived string:\n\tEXP: %v\n\tGOT: %v", ErrDerivedNotSettable, err)
+	}
+	if table.TypeOf(E(Label)) != TypeStr || TypeStr.String() != "String" {
+		t.Errorf("type error:\n\tEXP: %v\n\tGOT: %v", TypeStr, table.TypeOf(E(Label)))
+	}
+	table.RemoveDerived(E(Title))
+	if table.strs[uint32(Title)-uint32(_F64_PARAMS_END)] != "" {
+		t.Errorf("removed string value was not cleared")
+	}
+}
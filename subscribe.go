@@ -0,0 +1,256 @@
+package go_param_table
+
+import (
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// The listeners of the table. Only allocated by the first Subscribe(), so tables that never use
+// it pay for one nil pointer only
+type subscriptionState struct {
+	// guards params, the listener lists and notifying, so that a Watcher can be closed from
+	// another goroutine. Never held while a listener runs
+	mu     sync.Mutex
+	params map[uint32]*paramListeners
+	// changed subscribed indexes whose listeners have not been called yet
+	queue []uint32
+	// set while listeners are called, so that propagation started by a listener only queues
+	// its changes for the loop already running
+	notifying bool
+	// some listeners were unsubscribed while notifying and must still be removed
+	removed bool
+	nextID  uint64
+	// the id of the update whose changes are being notified, see ChangeEvent
+	batch uint64
+}
+
+type paramListeners struct {
+	// the value the listeners were last notified of, or that the parameter held when the
+	// first listener subscribed
+	last      savedValue
+	listeners []listener
+}
+
+type listener struct {
+	id uint64
+	// nil once unsubscribed
+	fn func(old, new *savedValue)
+}
+
+// Returned by Subscribe(), stops the listener from being called
+type Subscription struct {
+	unsubscribe func()
+}
+
+// Removes the listener. It is not called again, even if it is unsubscribed by another listener
+// of the same change. Calling Unsubscribe() more than once has no effect
+func (s Subscription) Unsubscribe() {
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+}
+
+func (s *subscriptionState) memoryFootprint() uintptr {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size := unsafe.Sizeof(*s)
+	size += uintptr(cap(s.queue)) * 4
+	for _, ps := range s.params {
+		// rough map estimate: one key and one value pointer per entry
+		size += 4 + unsafe.Sizeof(ps) + unsafe.Sizeof(*ps)
+		size += uintptr(cap(ps.listeners)) * unsafe.Sizeof(listener{})
+	}
+	return size
+}
+
+// Calls fn with the old and new value every time the parameter changes, once the update that
+// changed it is complete: after SetRoot_*() (or CommitBatch(), Undo(), UnmarshalBinary(), ...)
+// has propagated every change, so fn never sees a partially updated table. Changing a root
+// several times within a batch calls fn once, with the value from before the batch.
+//
+// Listeners are called once per changed parameter, parameters in the order they changed (roots
+// in the order they were set, then derived values in the order they were recalculated), and the
+// listeners of one parameter in the order they subscribed. A listener may set roots itself:
+// the values it changes are notified after the current ones, and values still waiting to be
+// notified report the value they have after it. Parameters with alwaysUpdate
+// notify their listeners every time they are set or recalculated, even with an unchanged value
+func Subscribe[T Scalar, E Index](t *ParamTableOf[E], p Param[T], fn func(old, new T)) Subscription {
+	typeIdx := typeIdxOf[T]()
+	t.checkIdxOfType(uint32(p), typeIdx, true)
+	return t.subscribe(uint32(p), typeIdx, func(old, new *savedValue) {
+		fn(savedAs[T](old, typeIdx), savedAs[T](new, typeIdx))
+	})
+}
+
+// Same as Subscribe(), for an opaque parameter. Whether it changed is decided by the equality func
+// it was initialized with
+func SubscribeVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], fn func(old, new T)) Subscription {
+	t.checkIdxOfType(uint32(p), typeVal, true)
+	return t.subscribe(uint32(p), typeVal, func(old, new *savedValue) {
+		oldVal, _ := old.val.(T)
+		newVal, _ := new.val.(T)
+		fn(oldVal, newVal)
+	})
+}
+
+// Same as Subscribe(), but returns an error instead of panicking
+func TrySubscribe[T Scalar, E Index](t *ParamTableOf[E], p Param[T], fn func(old, new T)) (Subscription, error) {
+	typeIdx := typeIdxOf[T]()
+	if err := t.idxTypeErr(uint32(p), typeNames[typeIdx], typeIdx, typeIdx == typeBool, true); err != nil {
+		return Subscription{}, err
+	}
+	return Subscribe(t, p, fn), nil
+}
+
+// Same as SubscribeVal(), but returns an error instead of panicking
+func TrySubscribeVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], fn func(old, new T)) (Subscription, error) {
+	if err := t.idxTypeErr(uint32(p), typeNames[typeVal], typeVal, false, true); err != nil {
+		return Subscription{}, err
+	}
+	if err := valTypeErr[T](t, uint32(p)); err != nil {
+		return Subscription{}, err
+	}
+	return SubscribeVal(t, p, fn), nil
+}
+
+func (t *ParamTableOf[E]) subscribe(idx uint32, typeIdx int, fn func(old, new *savedValue)) Subscription {
+	if t.subs == nil {
+		t.subs = &subscriptionState{params: make(map[uint32]*paramListeners)}
+	}
+	s := t.subs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ps := s.params[idx]
+	if ps == nil {
+		ps = &paramListeners{last: t.saveValue(idx, typeIdx)}
+		s.params[idx] = ps
+	}
+	s.nextID += 1
+	id := s.nextID
+	ps.listeners = append(ps.listeners, listener{id: id, fn: fn})
+	return Subscription{unsubscribe: func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		i := slices.IndexFunc(ps.listeners, func(l listener) bool { return l.id == id })
+		if i < 0 || ps.listeners[i].fn == nil {
+			return
+		}
+		ps.listeners[i].fn = nil
+		s.removed = true
+		if !s.notifying {
+			s.removeUnsubscribed()
+		}
+	}}
+}
+
+// Called with mu held
+func (s *subscriptionState) removeUnsubscribed() {
+	if !s.removed {
+		return
+	}
+	s.removed = false
+	for idx, ps := range s.params {
+		ps.listeners = slices.DeleteFunc(ps.listeners, func(l listener) bool { return l.fn == nil })
+		if len(ps.listeners) == 0 {
+			delete(s.params, idx)
+		}
+	}
+}
+
+// Queues the subscribed values among those changed by the current update. Called by propagate()
+// once every changed value is final
+func (t *ParamTableOf[E]) queueChanged() {
+	if t.subs == nil {
+		return
+	}
+	t.subs.mu.Lock()
+	defer t.subs.mu.Unlock()
+	for _, idx := range t.prop.changed {
+		if t.subs.params[uint32(idx)] != nil {
+			t.subs.queue = append(t.subs.queue, uint32(idx))
+		}
+	}
+}
+
+// Queues every subscribed value, for updates that write values without marking them changed
+// (Restore(), UnmarshalBinary() with derived values), then notifies the ones that changed
+func (t *ParamTableOf[E]) notifyAll() {
+	if t.subs == nil {
+		return
+	}
+	start := len(t.subs.queue)
+	t.subs.mu.Lock()
+	for idx := range t.subs.params {
+		t.subs.queue = append(t.subs.queue, idx)
+	}
+	t.subs.mu.Unlock()
+	slices.Sort(t.subs.queue[start:])
+	t.notify()
+}
+
+// Calls the listeners of every queued value that differs from the value they were last notified of
+func (t *ParamTableOf[E]) notify() {
+	s := t.subs
+	if s == nil || s.notifying {
+		return
+	}
+	if len(s.queue) == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.notifying = true
+	s.mu.Unlock()
+	s.batch += 1
+	defer func() {
+		clear(s.queue)
+		s.queue = s.queue[:0]
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.notifying = false
+		s.removeUnsubscribed()
+	}()
+	for i := 0; i < len(s.queue); i += 1 {
+		idx := s.queue[i]
+		s.mu.Lock()
+		ps := s.params[idx]
+		s.mu.Unlock()
+		if ps == nil {
+			continue
+		}
+		typeIdx := t.typeOfIdx(idx)
+		current := t.saveValue(idx, typeIdx)
+		if !getFlag(idx, t.flags).AlwaysUpdate() && t.savedEqual(idx, typeIdx, &ps.last, &current) {
+			continue
+		}
+		old := ps.last
+		ps.last = current
+		// listeners subscribed by a listener are only called for later changes. The list is not
+		// compacted while notifying, so the first count entries keep their place
+		s.mu.Lock()
+		count := len(ps.listeners)
+		s.mu.Unlock()
+		for j := 0; j < count; j += 1 {
+			s.mu.Lock()
+			fn := ps.listeners[j].fn
+			s.mu.Unlock()
+			if fn != nil {
+				fn(&old, &current)
+			}
+		}
+	}
+}
+
+// Returns a value copied by saveValue() as a T
+func savedAs[T Scalar](v *savedValue, typeIdx int) T {
+	switch typeIdx {
+	case typePtr:
+		return *(*T)(unsafe.Pointer(&v.ptr))
+	case typeStr:
+		return *(*T)(unsafe.Pointer(&v.str))
+	}
+	return *(*T)(unsafe.Pointer(&v.raw))
+}
@@ -0,0 +1,130 @@
+package go_param_table
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	t.Run("narrow", testSubscribe[uint16])
+	t.Run("wide", testSubscribe[uint32])
+}
+
+func testSubscribe[E Index](t *testing.T) {
+	// 0 -> 1, 2 -> 3 = 1 + 2
+	table, _ := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 1, false)
+	derive_F64(table, 1, _TEST_CALC_ADD_ONE, 0)
+	table.InitRoot_F64(2, 10, false)
+	derive_F64(table, 3, _TEST_CALC_SUM, 1, 2)
+	var log []string
+	var subs []Subscription
+	for idx := PIdx_F64(0); idx < 4; idx += 1 {
+		idx := idx
+		subs = append(subs, Subscribe(table, idx, func(old, new float64) {
+			// every listener sees the fully propagated table
+			if table.Get_F64(3) != table.Get_F64(1)+table.Get_F64(2) || table.Get_F64(1) != table.Get_F64(0)+1 {
+				t.Errorf("listener of %d saw a partially updated table", idx)
+			}
+			log = append(log, fmt.Sprintf("%d: %g -> %g", idx, old, new))
+		}))
+	}
+	var expectLog = func(name string, exp ...string) {
+		t.Helper()
+		if !slices.Equal(log, exp) {
+			t.Errorf("%s:\n\tEXP: %q\n\tGOT: %q", name, exp, log)
+		}
+		log = log[:0]
+	}
+
+	table.SetRoot_F64(0, 5)
+	expectLog("set", "0: 1 -> 5", "1: 2 -> 6", "3: 12 -> 16")
+	table.SetRoot_F64(0, 5)
+	expectLog("set unchanged")
+
+	table.BeginBatch()
+	table.SetRoot_F64(2, 20)
+	table.SetRoot_F64(0, 7)
+	table.SetRoot_F64(0, 8)
+	expectLog("inside batch")
+	table.CommitBatch()
+	expectLog("batch", "2: 10 -> 20", "0: 5 -> 8", "1: 6 -> 9", "3: 16 -> 29")
+
+	table.BeginBatch()
+	table.SetRoot_F64(0, 100)
+	table.Rollback()
+	expectLog("rollback")
+
+	subs[3].Unsubscribe()
+	subs[3].Unsubscribe()
+	table.SetRoot_F64(2, 21)
+	expectLog("unsubscribed", "2: 20 -> 21")
+
+	// changes made by a listener are notified after the current ones, values still waiting to
+	// be notified are reported as they are after it
+	reset := Subscribe(table, 1, func(old, new float64) {
+		if new > 100 {
+			table.SetRoot_F64(2, 0)
+		}
+	})
+	Subscribe(table, 3, func(old, new float64) {
+		log = append(log, fmt.Sprintf("3: %g -> %g", old, new))
+	})
+	table.SetRoot_F64(0, 200)
+	expectLog("set by listener", "0: 8 -> 200", "1: 9 -> 201", "3: 30 -> 201", "2: 21 -> 0")
+	reset.Unsubscribe()
+
+	snap := table.Snapshot()
+	table.SetRoot_F64(0, 1)
+	log = log[:0]
+	table.Restore(snap)
+	expectLog("restore", "0: 1 -> 200", "1: 2 -> 201", "3: 2 -> 201")
+
+	table.EnableHistory()
+	table.SetRoot_F64(2, 4)
+	log = log[:0]
+	table.Undo()
+	expectLog("undo", "2: 4 -> 0", "3: 205 -> 201")
+
+	if table.Clone().subs != nil {
+		t.Errorf("listeners copied to a clone")
+	}
+	if _, err := TrySubscribe(table, PIdx_U8(0), func(old, new uint8) {}); !errors.Is(err, ErrWrongType) {
+		t.Errorf("subscribe wrong type:\n\tEXP: %v\n\tGOT: %v", ErrWrongType, err)
+	}
+}
+
+func TestSubscribeVal(t *testing.T) {
+	t.Run("narrow", testSubscribeVal[uint16])
+	t.Run("wide", testSubscribeVal[uint32])
+}
+
+func testSubscribeVal[E Index](t *testing.T) {
+	b := newTestBuilderOf[E](WithDebug(true), WithVerbosity(VerbositySilent))
+	tags := DeclareVal[[]string](b, "tags")
+	flag := b.Bool("flag")
+	table := b.Build()
+	InitRootVal(&table, *tags, []string{"a"}, false, slices.Equal[[]string])
+	table.InitRoot_Bool(*flag, false, true)
+	var got [][]string
+	SubscribeVal(&table, *tags, func(old, new []string) {
+		got = append(got, old, new)
+	})
+	flags := 0
+	Subscribe(&table, *flag, func(old, new bool) {
+		flags += 1
+	})
+	SetVal(&table, *tags, []string{"a"})
+	SetVal(&table, *tags, []string{"b", "c"})
+	if len(got) != 2 || len(got[0]) != 1 || len(got[1]) != 2 {
+		t.Errorf("opaque value:\n\tEXP: [[a] [b c]]\n\tGOT: %v", got)
+	}
+	// alwaysUpdate values notify even when set to the same value
+	table.SetRoot_Bool(*flag, false)
+	table.SetRoot_Bool(*flag, false)
+	if flags != 2 {
+		t.Errorf("alwaysUpdate:\n\tEXP: 2 notifications\n\tGOT: %d", flags)
+	}
+}
@@ -0,0 +1,292 @@
+package go_param_table
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// The read-only part of the table API, implemented by ParamTableOf and passed to the funcs of
+// SyncParamTableOf.ReadTx()
+type ReaderOf[E Index] interface {
+	Get_U8(idx PIdx_U8) uint8
+	Get_I8(idx PIdx_I8) int8
+	Get_Bool(idx PIdx_Bool) bool
+	Get_U16(idx PIdx_U16) uint16
+	Get_I16(idx PIdx_I16) int16
+	Get_U32(idx PIdx_U32) uint32
+	Get_I32(idx PIdx_I32) int32
+	Get_F32(idx PIdx_F32) float32
+	Get_U64(idx PIdx_U64) uint64
+	Get_I64(idx PIdx_I64) int64
+	Get_F64(idx PIdx_F64) float64
+	Get_Ptr(idx PIdx_Ptr) unsafe.Pointer
+	Get_Str(idx PIdx_Str) string
+	IsInit(idx E) bool
+	IsDerived(idx E) bool
+	TypeOf(idx E) ParamType
+	Name(idx E) string
+	LookupByName(name string) (idx E, ok bool)
+}
+
+// The read-only API of a ParamTable
+type Reader = ReaderOf[uint16]
+
+// The read-only API of a WideParamTable
+type WideReader = ReaderOf[uint32]
+
+// A ParamTable that can be used from several goroutines at once, see SyncParamTableOf
+type SyncParamTable = SyncParamTableOf[uint16]
+
+// A WideParamTable that can be used from several goroutines at once, see SyncParamTableOf
+type WideSyncParamTable = SyncParamTableOf[uint32]
+
+// Wraps a table with a sync.RWMutex: writers (and the propagation, listeners and history
+// recording they cause) run one at a time, while any number of readers run concurrently
+// with each other but never with a writer, so they never see a partially propagated table.
+//
+// Single values can be read and set with the Get_*() and SetRoot_*() methods. Reads of
+// several values that must be consistent with each other go through ReadTx(), and everything
+// else (batches, InitDerived_*(), Subscribe(), opaque values, ...) through WriteTx()
+type SyncParamTableOf[E Index] struct {
+	mu    sync.RWMutex
+	table *ParamTableOf[E]
+}
+
+// Takes over the table, which must not be used directly afterwards
+func NewSyncParamTable[E Index](table *ParamTableOf[E]) *SyncParamTableOf[E] {
+	return &SyncParamTableOf[E]{table: table}
+}
+
+// Calls fn with the table under a read lock. Every value read within fn is from the same
+// fully propagated state. fn must not keep r after it returns
+func (s *SyncParamTableOf[E]) ReadTx(fn func(r ReaderOf[E])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(readOnly[E]{s.table})
+}
+
+// The ReaderOf passed to ReadTx(), which unlike the table itself cannot be type asserted back
+// to a *ParamTableOf and changed under the read lock
+type readOnly[E Index] struct {
+	table *ParamTableOf[E]
+}
+
+func (r readOnly[E]) Get_U8(idx PIdx_U8) uint8 {
+	return r.table.Get_U8(idx)
+}
+func (r readOnly[E]) Get_I8(idx PIdx_I8) int8 {
+	return r.table.Get_I8(idx)
+}
+func (r readOnly[E]) Get_Bool(idx PIdx_Bool) bool {
+	return r.table.Get_Bool(idx)
+}
+func (r readOnly[E]) Get_U16(idx PIdx_U16) uint16 {
+	return r.table.Get_U16(idx)
+}
+func (r readOnly[E]) Get_I16(idx PIdx_I16) int16 {
+	return r.table.Get_I16(idx)
+}
+func (r readOnly[E]) Get_U32(idx PIdx_U32) uint32 {
+	return r.table.Get_U32(idx)
+}
+func (r readOnly[E]) Get_I32(idx PIdx_I32) int32 {
+	return r.table.Get_I32(idx)
+}
+func (r readOnly[E]) Get_F32(idx PIdx_F32) float32 {
+	return r.table.Get_F32(idx)
+}
+func (r readOnly[E]) Get_U64(idx PIdx_U64) uint64 {
+	return r.table.Get_U64(idx)
+}
+func (r readOnly[E]) Get_I64(idx PIdx_I64) int64 {
+	return r.table.Get_I64(idx)
+}
+func (r readOnly[E]) Get_F64(idx PIdx_F64) float64 {
+	return r.table.Get_F64(idx)
+}
+func (r readOnly[E]) Get_Ptr(idx PIdx_Ptr) unsafe.Pointer {
+	return r.table.Get_Ptr(idx)
+}
+func (r readOnly[E]) Get_Str(idx PIdx_Str) string {
+	return r.table.Get_Str(idx)
+}
+func (r readOnly[E]) IsInit(idx E) bool {
+	return r.table.IsInit(idx)
+}
+func (r readOnly[E]) IsDerived(idx E) bool {
+	return r.table.IsDerived(idx)
+}
+func (r readOnly[E]) TypeOf(idx E) ParamType {
+	return r.table.TypeOf(idx)
+}
+func (r readOnly[E]) Name(idx E) string {
+	return r.table.Name(idx)
+}
+func (r readOnly[E]) LookupByName(name string) (idx E, ok bool) {
+	return r.table.LookupByName(name)
+}
+
+// Calls fn with the table under the write lock, for any change of the table. Readers see
+// every change made within fn at once, after fn returns. fn must not keep t after it returns,
+// and listeners subscribed within it must not call methods of s, which would deadlock
+func (s *SyncParamTableOf[E]) WriteTx(fn func(t *ParamTableOf[E])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.table)
+}
+
+func (s *SyncParamTableOf[E]) Get_U8(idx PIdx_U8) uint8 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_U8(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_I8(idx PIdx_I8) int8 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_I8(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_Bool(idx PIdx_Bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_Bool(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_U16(idx PIdx_U16) uint16 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_U16(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_I16(idx PIdx_I16) int16 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_I16(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_U32(idx PIdx_U32) uint32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_U32(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_I32(idx PIdx_I32) int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_I32(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_F32(idx PIdx_F32) float32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_F32(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_U64(idx PIdx_U64) uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_U64(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_I64(idx PIdx_I64) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_I64(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_F64(idx PIdx_F64) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_F64(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_Ptr(idx PIdx_Ptr) unsafe.Pointer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_Ptr(idx)
+}
+
+func (s *SyncParamTableOf[E]) Get_Str(idx PIdx_Str) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get_Str(idx)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_U8(idx PIdx_U8, val uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_U8(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_I8(idx PIdx_I8, val int8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_I8(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_Bool(idx PIdx_Bool, val bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_Bool(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_U16(idx PIdx_U16, val uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_U16(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_I16(idx PIdx_I16, val int16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_I16(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_U32(idx PIdx_U32, val uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_U32(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_I32(idx PIdx_I32, val int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_I32(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_F32(idx PIdx_F32, val float32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_F32(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_U64(idx PIdx_U64, val uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_U64(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_I64(idx PIdx_I64, val int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_I64(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_F64(idx PIdx_F64, val float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_F64(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_Ptr(idx PIdx_Ptr, val unsafe.Pointer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_Ptr(idx, val)
+}
+
+func (s *SyncParamTableOf[E]) SetRoot_Str(idx PIdx_Str, val string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.SetRoot_Str(idx, val)
+}
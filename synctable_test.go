@@ -0,0 +1,77 @@
+package go_param_table
+
+import (
+	"sync"
+	"testing"
+)
+
+var _ Reader = (*ParamTable)(nil)
+var _ WideReader = (*WideParamTable)(nil)
+
+func TestSyncParamTableStress(t *testing.T) {
+	t.Run("narrow", testSyncParamTableStress[uint16])
+	t.Run("wide", testSyncParamTableStress[uint32])
+}
+
+func testSyncParamTableStress[E Index](t *testing.T) {
+	// 0 + 1 -> 2 -> 3
+	table, _ := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 0, false)
+	table.InitRoot_F64(1, 0, false)
+	derive_F64(table, 2, _TEST_CALC_SUM, 0, 1)
+	derive_F64(table, 3, _TEST_CALC_ADD_ONE, 2)
+	s := NewSyncParamTable(table)
+	const writers, readers, iterations = 4, 4, 500
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w += 1 {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i += 1 {
+				if i%2 == 0 {
+					s.SetRoot_F64(0, float64(w*iterations+i))
+					continue
+				}
+				// both roots change together, readers never see only one of them
+				s.WriteTx(func(t *ParamTableOf[E]) {
+					t.BeginBatch()
+					t.SetRoot_F64(0, float64(i))
+					t.SetRoot_F64(1, float64(-i))
+					t.CommitBatch()
+				})
+			}
+		}(w)
+	}
+	errs := make(chan string, readers)
+	for r := 0; r < readers; r += 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i += 1 {
+				s.ReadTx(func(r ReaderOf[E]) {
+					a, b, sum, next := r.Get_F64(0), r.Get_F64(1), r.Get_F64(2), r.Get_F64(3)
+					if sum != a+b || next != sum+1 {
+						select {
+						case errs <- "read a partially propagated table":
+						default:
+						}
+					}
+				})
+				_ = s.Get_F64(3)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	s.ReadTx(func(r ReaderOf[E]) {
+		if r.Get_F64(2) != r.Get_F64(0)+r.Get_F64(1) {
+			t.Errorf("final state is inconsistent")
+		}
+		if _, ok := r.(*ParamTableOf[E]); ok {
+			t.Errorf("reader can be type asserted to the table and changed under the read lock")
+		}
+	})
+}
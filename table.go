@@ -1,36 +1,47 @@
 package go_param_table
 
 import (
-	"fmt"
 	"io"
 	"os"
 	"slices"
 	"unsafe"
 )
 
-// Whether or not any ParamTable's insert additional sanity checks on input/output operations
+// The default for whether newly created ParamTable's insert additional sanity checks on input/output operations.
+// Each table copies this value when created, use WithDebug() or ParamTable.Configure() to change it per table
 //   - true (default): recommended for develpoment, or any time your program may be stuck in an infinite loop or parameters are not behaving as expected
 //   - false: if you want slightly faster speed in production, have already fully tested your param table with EnableDebug set to true, _AND_ your end users do not have direct access to your ParamTable
 var EnableDebug bool = true
 
-// The output writer for debug messages. If `EnableDebug == false`, this will not be used at all
+// The default output writer for debug messages of newly created ParamTable's, see WithDebugWriter()
 var DebugWriter io.Writer = os.Stderr
 
+// The index types a ParamTableOf can store its dependency graph with
+type Index interface {
+	uint16 | uint32
+}
+
+type (
+	ParamCalc            = ParamCalcOf[uint16]
+	WideParamCalc        = ParamCalcOf[uint32]
+	ParamCalcOf[E Index] func(c *CalcInterfaceOf[E])
+	PIdx_Calc            uint32
+)
+
 type (
-	ParamCalc func(c *CalcInterface)
-	PIdx_U64  uint16
-	PIdx_I64  uint16
-	PIdx_F64  uint16
-	PIdx_Ptr  uint16
-	PIdx_U32  uint16
-	PIdx_I32  uint16
-	PIdx_F32  uint16
-	PIdx_U16  uint16
-	PIdx_I16  uint16
-	PIdx_U8   uint16
-	PIdx_I8   uint16
-	PIdx_Bool uint16
-	PIdx_Calc uint16
+	PIdx_U64  = Param[uint64]
+	PIdx_I64  = Param[int64]
+	PIdx_F64  = Param[float64]
+	PIdx_Ptr  = Param[unsafe.Pointer]
+	PIdx_Str  = Param[string]
+	PIdx_U32  = Param[uint32]
+	PIdx_I32  = Param[int32]
+	PIdx_F32  = Param[float32]
+	PIdx_U16  = Param[uint16]
+	PIdx_I16  = Param[int16]
+	PIdx_U8   = Param[uint8]
+	PIdx_I8   = Param[int8]
+	PIdx_Bool = Param[bool]
 )
 
 const (
@@ -38,6 +49,8 @@ const (
 	typeI64
 	typeF64
 	typePtr
+	typeStr
+	typeVal
 	typeU32
 	typeI32
 	typeF32
@@ -52,13 +65,18 @@ const (
 const (
 	size64  uint32 = 8
 	sizePtr uint32 = uint32(unsafe.Sizeof(unsafe.Pointer(nil)))
+	sizeStr uint32 = uint32(unsafe.Sizeof(""))
+	sizeVal uint32 = uint32(unsafe.Sizeof(any(nil)))
 	size32  uint32 = 4
 	size16  uint32 = 2
 	size8   uint32 = 1
 )
 
 const (
+	// The null index of a ParamTable, never a valid parameter or calc index
 	PIDX_NULL uint16 = 65535
+	// The null index of a WideParamTable
+	WIDE_PIDX_NULL uint32 = 4294967295
 )
 
 var sizeTable = [typeCount]uint32{
@@ -66,6 +84,8 @@ var sizeTable = [typeCount]uint32{
 	typeI64:  size64,
 	typeF64:  size64,
 	typePtr:  sizePtr,
+	typeStr:  sizeStr,
+	typeVal:  sizeVal,
 	typeU32:  size32,
 	typeI32:  size32,
 	typeF32:  size32,
@@ -97,883 +117,848 @@ func (f paramFlags) AlwaysUpdate() bool {
 	return f&_PFLAG_ALWAYS_UPDATE == _PFLAG_ALWAYS_UPDATE
 }
 
-func getFlag(elemIdx uint16, blocks []paramFlags) paramFlags {
+func getFlag(elemIdx uint32, blocks []paramFlags) paramFlags {
 	bIdx := elemIdx >> _PFLAG_SUB_PER_CHUNK_SHIFT
 	sIdx := elemIdx % _PFLAG_SUB_PER_CHUNK
 	block := blocks[bIdx]
 	return (block >> (sIdx * _PFLAG_BITS)) & _PFLAG_MASK
 }
 
-func setFlag(elemIdx uint16, blocks []paramFlags, val paramFlags) {
+func setFlag(elemIdx uint32, blocks []paramFlags, val paramFlags) {
 	bIdx := elemIdx >> _PFLAG_SUB_PER_CHUNK_SHIFT
 	sIdx := elemIdx % _PFLAG_SUB_PER_CHUNK
 	block := val << paramFlags(sIdx*_PFLAG_BITS)
 	blocks[bIdx] |= block
 }
 
-func initFlagLen(elemCount uint16) int {
+func clearFlag(elemIdx uint32, blocks []paramFlags, val paramFlags) {
+	bIdx := elemIdx >> _PFLAG_SUB_PER_CHUNK_SHIFT
+	sIdx := elemIdx % _PFLAG_SUB_PER_CHUNK
+	block := val << paramFlags(sIdx*_PFLAG_BITS)
+	blocks[bIdx] &^= block
+}
+
+func initFlagLen(elemCount uint32) int {
 	return int(elemCount+_PFLAG_SUB_PER_CHUNK_MINUS_ONE) >> _PFLAG_SUB_PER_CHUNK_SHIFT
 }
 
-type ParamTable struct {
-	values      []byte
-	flags       []paramFlags
-	hookups     []hookup
-	hookupData  []uint16
-	calcs       []ParamCalc
-	byteOffsets [typeCount]uint32
-	idxOffsets  [typeCount]uint16
+// A table of parameters that stores its dependency graph with 16-bit indexes, so it holds at
+// most 65535 parameters and calcs, each calc having at most 255 inputs and 255 outputs.
+// This is the default, see WideParamTable for larger tables
+type ParamTable = ParamTableOf[uint16]
+
+// A table of parameters that stores its dependency graph with 32-bit indexes, lifting the limits
+// of ParamTable to 4294967295 parameters and calcs and 65535 inputs and outputs per calc, at the
+// cost of twice the memory for the dependency graph. It has the same API as ParamTable, except
+// that raw index lists (calc inputs and outputs, introspection results) are []uint32
+type WideParamTable = ParamTableOf[uint32]
+
+type ParamTableOf[E Index] struct {
+	values []byte
+	// pointer, string and opaque values are kept out of values, which the garbage collector does not scan
+	ptrs []unsafe.Pointer
+	strs []string
+	vals []any
+	// the equality func each opaque value was initialized with, nil if every write is a change
+	valEqual   []func(a, b any) bool
+	flags      []paramFlags
+	hookups    []hookup
+	hookupData []E
+	// number of hookupData entries no longer owned by any segment
+	hookupGarbage uint32
+	// changes with every change of the dependency graph, see Snapshot()
+	structure uint64
+	calcs     []ParamCalcOf[E]
+	prop      propState[E]
+	batch     batchState
+	history   *historyState
+	subs      *subscriptionState
+	front     *frontBuffer
+	parallel  *parallelState[E]
+	// the calcs registered with WithParallelSafe(), nil if there are none
+	parallelCalcs []bool
+	debug         debugConfig
+	meta          metaRegistry[E]
+	byteOffsets   [typeCount]uint32
+	idxOffsets    [typeCount]uint32
+}
+
+// Creates a new table from the END index of each parameter type region (see the README template
+// for the expected constant layout). Panics if the type regions are not in order.
+//
+// The string and opaque value regions are left empty, use NewParamTableFromLayout() for a table
+// with string or opaque parameters
+func NewParamTable(typeU64End PIdx_U64, typeI64End PIdx_I64, typeF64End PIdx_F64, typePtrEnd PIdx_Ptr, typeU32End PIdx_U32, typeI32End PIdx_I32, typeF32End PIdx_F32, typeU16End PIdx_U16, typeI16End PIdx_I16, typeU8End PIdx_U8, typeI8End PIdx_I8, typeBoolEnd PIdx_Bool, calcsCount PIdx_Calc, opts ...TableOption) ParamTable {
+	table, err := TryNewParamTable(typeU64End, typeI64End, typeF64End, typePtrEnd, typeU32End, typeI32End, typeF32End, typeU16End, typeI16End, typeU8End, typeI8End, typeBoolEnd, calcsCount, opts...)
+	if err != nil {
+		table.fail(err)
+	}
+	return table
+}
+
+// Same as NewParamTable(), but returns an ErrLayout error instead of panicking
+func TryNewParamTable(typeU64End PIdx_U64, typeI64End PIdx_I64, typeF64End PIdx_F64, typePtrEnd PIdx_Ptr, typeU32End PIdx_U32, typeI32End PIdx_I32, typeF32End PIdx_F32, typeU16End PIdx_U16, typeI16End PIdx_I16, typeU8End PIdx_U8, typeI8End PIdx_I8, typeBoolEnd PIdx_Bool, calcsCount PIdx_Calc, opts ...TableOption) (ParamTable, error) {
+	ends := layoutEnds(typeU64End, typeI64End, typeF64End, typePtrEnd, typeU32End, typeI32End, typeF32End, typeU16End, typeI16End, typeU8End, typeI8End, typeBoolEnd)
+	return tryNewParamTableOf[uint16](ends, calcsCount, opts...)
+}
+
+// Same as NewParamTable(), but creates a WideParamTable
+func NewWideParamTable(typeU64End PIdx_U64, typeI64End PIdx_I64, typeF64End PIdx_F64, typePtrEnd PIdx_Ptr, typeU32End PIdx_U32, typeI32End PIdx_I32, typeF32End PIdx_F32, typeU16End PIdx_U16, typeI16End PIdx_I16, typeU8End PIdx_U8, typeI8End PIdx_I8, typeBoolEnd PIdx_Bool, calcsCount PIdx_Calc, opts ...TableOption) WideParamTable {
+	table, err := TryNewWideParamTable(typeU64End, typeI64End, typeF64End, typePtrEnd, typeU32End, typeI32End, typeF32End, typeU16End, typeI16End, typeU8End, typeI8End, typeBoolEnd, calcsCount, opts...)
+	if err != nil {
+		table.fail(err)
+	}
+	return table
+}
+
+// Same as TryNewParamTable(), but creates a WideParamTable
+func TryNewWideParamTable(typeU64End PIdx_U64, typeI64End PIdx_I64, typeF64End PIdx_F64, typePtrEnd PIdx_Ptr, typeU32End PIdx_U32, typeI32End PIdx_I32, typeF32End PIdx_F32, typeU16End PIdx_U16, typeI16End PIdx_I16, typeU8End PIdx_U8, typeI8End PIdx_I8, typeBoolEnd PIdx_Bool, calcsCount PIdx_Calc, opts ...TableOption) (WideParamTable, error) {
+	ends := layoutEnds(typeU64End, typeI64End, typeF64End, typePtrEnd, typeU32End, typeI32End, typeF32End, typeU16End, typeI16End, typeU8End, typeI8End, typeBoolEnd)
+	return tryNewParamTableOf[uint32](ends, calcsCount, opts...)
+}
+
+func layoutEnds(typeU64End PIdx_U64, typeI64End PIdx_I64, typeF64End PIdx_F64, typePtrEnd PIdx_Ptr, typeU32End PIdx_U32, typeI32End PIdx_I32, typeF32End PIdx_F32, typeU16End PIdx_U16, typeI16End PIdx_I16, typeU8End PIdx_U8, typeI8End PIdx_I8, typeBoolEnd PIdx_Bool) [typeCount]uint32 {
+	return [typeCount]uint32{
+		typeU64:  uint32(typeU64End),
+		typeI64:  uint32(typeI64End),
+		typeF64:  uint32(typeF64End),
+		typePtr:  uint32(typePtrEnd),
+		typeStr:  uint32(typePtrEnd),
+		typeVal:  uint32(typePtrEnd),
+		typeU32:  uint32(typeU32End),
+		typeI32:  uint32(typeI32End),
+		typeF32:  uint32(typeF32End),
+		typeU16:  uint32(typeU16End),
+		typeI16:  uint32(typeI16End),
+		typeU8:   uint32(typeU8End),
+		typeI8:   uint32(typeI8End),
+		typeBool: uint32(typeBoolEnd),
+	}
+}
+
+// The END index of each parameter type region of a table, for NewParamTableFromLayout(). The
+// regions are in the order of the fields, and a zero END leaves its region empty (it ends where
+// the previous one does), so only the regions a table uses need to be set
+type ParamLayout struct {
+	U64End  PIdx_U64
+	I64End  PIdx_I64
+	F64End  PIdx_F64
+	PtrEnd  PIdx_Ptr
+	StrEnd  PIdx_Str
+	ValEnd  PIdx_Val[any]
+	U32End  PIdx_U32
+	I32End  PIdx_I32
+	F32End  PIdx_F32
+	U16End  PIdx_U16
+	I16End  PIdx_I16
+	U8End   PIdx_U8
+	I8End   PIdx_I8
+	BoolEnd PIdx_Bool
+}
+
+func (l ParamLayout) ends() [typeCount]uint32 {
+	ends := [typeCount]uint32{
+		typeU64:  uint32(l.U64End),
+		typeI64:  uint32(l.I64End),
+		typeF64:  uint32(l.F64End),
+		typePtr:  uint32(l.PtrEnd),
+		typeStr:  uint32(l.StrEnd),
+		typeVal:  uint32(l.ValEnd),
+		typeU32:  uint32(l.U32End),
+		typeI32:  uint32(l.I32End),
+		typeF32:  uint32(l.F32End),
+		typeU16:  uint32(l.U16End),
+		typeI16:  uint32(l.I16End),
+		typeU8:   uint32(l.U8End),
+		typeI8:   uint32(l.I8End),
+		typeBool: uint32(l.BoolEnd),
+	}
+	for typeIdx := 1; typeIdx < typeCount; typeIdx += 1 {
+		if ends[typeIdx] == 0 {
+			ends[typeIdx] = ends[typeIdx-1]
+		}
+	}
+	return ends
+}
+
+// Creates a new table with every parameter type region, including the string and opaque value
+// regions NewParamTable() leaves empty. Panics if the type regions are not in order
+func NewParamTableFromLayout(layout ParamLayout, calcsCount PIdx_Calc, opts ...TableOption) ParamTable {
+	table, err := TryNewParamTableFromLayout(layout, calcsCount, opts...)
+	if err != nil {
+		table.fail(err)
+	}
+	return table
+}
+
+// Same as NewParamTableFromLayout(), but returns an ErrLayout error instead of panicking
+func TryNewParamTableFromLayout(layout ParamLayout, calcsCount PIdx_Calc, opts ...TableOption) (ParamTable, error) {
+	return tryNewParamTableOf[uint16](layout.ends(), calcsCount, opts...)
+}
+
+// Same as NewParamTableFromLayout(), but creates a WideParamTable
+func NewWideParamTableFromLayout(layout ParamLayout, calcsCount PIdx_Calc, opts ...TableOption) WideParamTable {
+	table, err := TryNewWideParamTableFromLayout(layout, calcsCount, opts...)
+	if err != nil {
+		table.fail(err)
+	}
+	return table
+}
+
+// Same as TryNewParamTableFromLayout(), but creates a WideParamTable
+func TryNewWideParamTableFromLayout(layout ParamLayout, calcsCount PIdx_Calc, opts ...TableOption) (WideParamTable, error) {
+	return tryNewParamTableOf[uint32](layout.ends(), calcsCount, opts...)
 }
 
-func NewParamTable(typeU64End PIdx_U64, typeI64End PIdx_I64, typeF64End PIdx_F64, typePtrEnd PIdx_Ptr, typeU32End PIdx_U32, typeI32End PIdx_I32, typeF32End PIdx_F32, typeU16End PIdx_U16, typeI16End PIdx_I16, typeU8End PIdx_U8, typeI8End PIdx_I8, typeBoolEnd PIdx_Bool, calcsCount PIdx_Calc) ParamTable {
-	if typeU64End > PIdx_U64(typeI64End) || typeI64End > PIdx_I64(typeF64End) || typeF64End > PIdx_F64(typePtrEnd) ||
-		typePtrEnd > PIdx_Ptr(typeU32End) ||
-		typeU32End > PIdx_U32(typeI32End) || typeI32End > PIdx_I32(typeF32End) || typeF32End > PIdx_F32(typeU16End) ||
-		typeU16End > PIdx_U16(typeI16End) || typeI16End > PIdx_I16(typeU8End) ||
-		typeU8End > PIdx_U8(typeI8End) || typeI8End > PIdx_I8(typeBoolEnd) {
-		fmt.Fprint(DebugWriter, `fatal: go_param_table: NewParamTable(): indexes not in order: all parameter index ends MUST be in this EXACT order from smallest to largest:
+func tryNewParamTableOf[E Index](ends [typeCount]uint32, calcsCount PIdx_Calc, opts ...TableOption) (ParamTableOf[E], error) {
+	if err := layoutErr[E](ends, calcsCount); err != nil {
+		table := ParamTableOf[E]{debug: defaultDebugConfig()}
+		table.Configure(opts...)
+		return table, err
+	}
+	return newParamTableFromEnds[E](ends, calcsCount, opts...), nil
+}
+
+func layoutErr[E Index](ends [typeCount]uint32, calcsCount PIdx_Calc) error {
+	null := ^E(0)
+	for typeIdx := 1; typeIdx < typeCount; typeIdx += 1 {
+		if ends[typeIdx-1] > ends[typeIdx] {
+			return newParamError(ErrLayout, null, `NewParamTable(): indexes not in order: all parameter index ends MUST be in this EXACT order from smallest to largest:
 	typeU64End <= typeI64End <= typeF64End <=
-	typePtrEnd <=
+	typePtrEnd <= typeStrEnd <= typeValEnd <=
 	typeU32End <= typeI32End <= typeF32End <=
 	typeU16End <= typeI16End <=
 	typeU8End <= typeI8End <= typeBoolEnd
 For an example template that fulfills this requirement, see the function body of 'paratable.TestParamTable(t *testing.T)' or the doc-comment of 'paratable.PARAM_TABLE_TEMPLATE_DOC_COMMENT'`)
-		panic(1)
-	}
-	var idxOffsets = [typeCount]uint16{
-		typeU64:  0,
-		typeI64:  uint16(typeU64End),
-		typeF64:  uint16(typeI64End),
-		typePtr:  uint16(typePtrEnd),
-		typeU32:  uint16(typeF64End),
-		typeI32:  uint16(typeU32End),
-		typeF32:  uint16(typeI32End),
-		typeU16:  uint16(typeF32End),
-		typeI16:  uint16(typeU16End),
-		typeU8:   uint16(typeI16End),
-		typeI8:   uint16(typeU8End),
-		typeBool: uint16(typeI8End),
-	}
-	var valuesIdxLen = typeBoolEnd
-	var byteOffsets = [typeCount]uint32{
-		typeU64:  0,
-		typeI64:  uint32(typeU64End) * 8,
-		typeF64:  uint32(typeI64End) * 8,
-		typePtr:  uint32(typeF64End) * 8,
-		typeU32:  (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr),
-		typeI32:  (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr) + (uint32(PIdx_Ptr(typeU32End)-typePtrEnd) * 4),
-		typeF32:  (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr) + (uint32(PIdx_Ptr(typeI32End)-typePtrEnd) * 4),
-		typeU16:  (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr) + (uint32(PIdx_Ptr(typeF32End)-typePtrEnd) * 4),
-		typeI16:  (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr) + (uint32(PIdx_Ptr(typeF32End)-typePtrEnd) * 4) + (uint32(PIdx_F32(typeU16End)-typeF32End) * 2),
-		typeU8:   (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr) + (uint32(PIdx_Ptr(typeF32End)-typePtrEnd) * 4) + (uint32(PIdx_F32(typeI16End)-typeF32End) * 2),
-		typeI8:   (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr) + (uint32(PIdx_Ptr(typeF32End)-typePtrEnd) * 4) + (uint32(PIdx_F32(typeI16End)-typeF32End) * 2) + uint32(PIdx_I16(typeU8End)-typeI16End),
-		typeBool: (uint32(typeF64End) * 8) + (uint32(PIdx_F64(typePtrEnd)-typeF64End) * sizePtr) + (uint32(PIdx_Ptr(typeF32End)-typePtrEnd) * 4) + (uint32(PIdx_F32(typeI16End)-typeF32End) * 2) + uint32(PIdx_I16(typeI8End)-typeI16End),
-	}
-	var valuesByteLen = byteOffsets[typeBool] + uint32(PIdx_I16(typeBoolEnd)-typeI16End)
+		}
+	}
+	if ends[typeBool] > uint32(null) || uint32(calcsCount) > uint32(null) {
+		return newParamError(ErrLayout, null, "NewParamTable(): %d parameters and %d calcs requested, but this table holds at most %d of each (see WideParamTable)", ends[typeBool], calcsCount, null)
+	}
+	var byteLen uint64
+	for typeIdx := 0; typeIdx < typeCount; typeIdx += 1 {
+		start := uint32(0)
+		if typeIdx > 0 {
+			start = ends[typeIdx-1]
+		}
+		byteLen += uint64(ends[typeIdx]-start) * uint64(byteSizeOf(typeIdx))
+	}
+	if byteLen > uint64(^uint32(0)) {
+		return newParamError(ErrLayout, null, "NewParamTable(): parameter values would take %d bytes (max %d)", byteLen, ^uint32(0))
+	}
+	return nil
+}
+
+// The number of bytes a value of the type takes up in the values slice
+func byteSizeOf(typeIdx int) uint32 {
+	if typeIdx == typePtr || typeIdx == typeStr || typeIdx == typeVal {
+		return 0
+	}
+	return sizeTable[typeIdx]
+}
+
+// Creates a table from the (already validated) END index of each type region
+func newParamTableFromEnds[E Index](ends [typeCount]uint32, calcsCount PIdx_Calc, opts ...TableOption) ParamTableOf[E] {
+	var idxOffsets [typeCount]uint32
+	var byteOffsets [typeCount]uint32
+	for typeIdx := 1; typeIdx < typeCount; typeIdx += 1 {
+		idxOffsets[typeIdx] = ends[typeIdx-1]
+		byteOffsets[typeIdx] = byteOffsets[typeIdx-1] + ((ends[typeIdx-1] - idxOffsets[typeIdx-1]) * byteSizeOf(typeIdx-1))
+	}
+	var valuesIdxLen = ends[typeBool]
+	var valuesByteLen = byteOffsets[typeBool] + ((ends[typeBool] - idxOffsets[typeBool]) * byteSizeOf(typeBool))
 	valuesSlice := make([]byte, valuesByteLen)
+	ptrsSlice := make([]unsafe.Pointer, ends[typePtr]-idxOffsets[typePtr])
+	strsSlice := make([]string, ends[typeStr]-idxOffsets[typeStr])
+	valsLen := ends[typeVal] - idxOffsets[typeVal]
 	hookupsSlice := make([]hookup, valuesIdxLen)
-	calcsSlice := make([]ParamCalc, calcsCount)
-	hookupsDataSlice := make([]uint16, 1)
-	flagsLen := initFlagLen(uint16(valuesIdxLen))
+	calcsSlice := make([]ParamCalcOf[E], calcsCount)
+	hookupsDataSlice := make([]E, 1)
+	flagsLen := initFlagLen(valuesIdxLen)
 	flags := make([]paramFlags, flagsLen)
-	return ParamTable{
+	table := ParamTableOf[E]{
 		values:      valuesSlice,
+		ptrs:        ptrsSlice,
+		strs:        strsSlice,
+		vals:        make([]any, valsLen),
+		valEqual:    make([]func(a, b any) bool, valsLen),
 		hookupData:  hookupsDataSlice,
 		hookups:     hookupsSlice,
 		flags:       flags,
 		calcs:       calcsSlice,
+		prop:        newPropState[E](valuesIdxLen),
 		byteOffsets: byteOffsets,
 		idxOffsets:  idxOffsets,
+		structure:   nextStructureID(),
+		debug:       defaultDebugConfig(),
 	}
+	table.Configure(opts...)
+	return table
 }
 
-func (t *ParamTable) TotalMemoryFootprint() uintptr {
+func (t *ParamTableOf[E]) TotalMemoryFootprint() uintptr {
 	size := unsafe.Sizeof(*t)
 	size += uintptr(cap(t.values))
-	size += uintptr(cap(t.hookupData)) * 2
+	size += uintptr(cap(t.ptrs)) * uintptr(sizePtr)
+	size += uintptr(cap(t.strs)) * uintptr(sizeStr)
+	for _, str := range t.strs {
+		size += uintptr(len(str))
+	}
+	// only the interface headers of opaque values, what they point to is not known
+	size += uintptr(cap(t.vals)) * uintptr(sizeVal)
+	size += uintptr(cap(t.valEqual)) * unsafe.Sizeof((func(a, b any) bool)(nil))
+	size += uintptr(cap(t.hookupData)) * unsafe.Sizeof(E(0))
 	size += uintptr(cap(t.flags)) * unsafe.Sizeof(paramFlags(0))
 	size += uintptr(cap(t.hookups)) * 4
-	size += uintptr(cap(t.calcs)) * unsafe.Sizeof((ParamCalc)(nil))
+	size += uintptr(cap(t.calcs)) * unsafe.Sizeof((ParamCalcOf[E])(nil))
+	size += t.prop.memoryFootprint()
+	size += t.batch.memoryFootprint()
+	size += t.history.memoryFootprint()
+	size += t.subs.memoryFootprint()
+	size += t.front.memoryFootprint()
+	size += t.parallel.memoryFootprint()
+	size += uintptr(cap(t.parallelCalcs))
+	size += t.meta.memoryFootprint()
 	return size
 }
 
-func (t *ParamTable) checkInit(idx uint16) {
-	if EnableDebug {
-		f := getFlag(idx, t.flags)
-		if !f.IsInit() {
-			fmt.Fprintf(DebugWriter, "fatal: go_param_table: parameter index %d was never initialized", idx)
-			panic(1)
+func (t *ParamTableOf[E]) checkInit(idx uint32) {
+	if t.debug.enabled {
+		if err := t.initErr(idx); err != nil {
+			t.fail(err)
 		}
 	}
 }
 
-func (t *ParamTable) checkIdxType(idx uint16, name string, validType int, final bool, canBeDerived bool) {
-	if EnableDebug {
-		if idx >= uint16(len(t.hookups)) {
-			fmt.Fprintf(DebugWriter, "fatal: go_param_table: index %d is outside bounds of parameter list (len %d)", idx, len(t.hookups))
-			panic(1)
-		}
-		if final {
-			if idx < t.idxOffsets[validType] {
-				fmt.Fprintf(DebugWriter, "fatal: go_param_table: index %d is not a %s value: %s values are in range [%d, %d)", idx, name, name, t.idxOffsets[validType], len(t.hookupData))
-				panic(1)
-			}
-		} else {
-			if idx < t.idxOffsets[validType] && idx >= t.idxOffsets[validType+1] {
-				fmt.Fprintf(DebugWriter, "fatal: go_param_table: index %d is not a %s value: %s values are in range [%d, %d)", idx, name, name, t.idxOffsets[validType], t.idxOffsets[validType+1])
-				panic(1)
-			}
-		}
-		if !canBeDerived {
-			if t.isDerived(idx) {
-				fmt.Fprintf(DebugWriter, "fatal: go_param_table: index %d is a derived value (has parents and calculation func), cannot update directly", idx)
-				panic(1)
-			}
+func (t *ParamTableOf[E]) initErr(idx uint32) error {
+	if idx >= uint32(len(t.hookups)) {
+		return newParamError(ErrIndexOutOfRange, idx, "index %s is outside bounds of parameter list (len %d)", t.paramLabel(idx), len(t.hookups))
+	}
+	if !getFlag(idx, t.flags).IsInit() {
+		return newParamError(ErrNotInitialized, idx, "parameter index %s was never initialized", t.paramLabel(idx))
+	}
+	return nil
+}
+
+func (t *ParamTableOf[E]) checkIdxType(idx uint32, name string, validType int, final bool, canBeDerived bool) {
+	if t.debug.enabled {
+		if err := t.idxTypeErr(idx, name, validType, final, canBeDerived); err != nil {
+			t.fail(err)
 		}
 	}
 }
 
-func (t *ParamTable) getBytePtr(idx uint16, typeIdx int) (ptr *byte, subIdx uint16) {
+func (t *ParamTableOf[E]) idxTypeErr(idx uint32, name string, validType int, final bool, canBeDerived bool) error {
+	if idx >= uint32(len(t.hookups)) {
+		return newParamError(ErrIndexOutOfRange, idx, "index %s is outside bounds of parameter list (len %d)", t.paramLabel(idx), len(t.hookups))
+	}
+	typeEnd := uint32(len(t.hookups))
+	if !final {
+		typeEnd = t.idxOffsets[validType+1]
+	}
+	if idx < t.idxOffsets[validType] || idx >= typeEnd {
+		return newParamError(ErrWrongType, idx, "index %s is not a %s value: %s values are in range [%d, %d)", t.paramLabel(idx), name, name, t.idxOffsets[validType], typeEnd)
+	}
+	if !canBeDerived && t.isDerived(E(idx)) {
+		return newParamError(ErrDerivedNotSettable, idx, "index %s is a derived value (has parents and calculation func), cannot update directly", t.paramLabel(idx))
+	}
+	return nil
+}
+
+// Returns the storage of idx. Pointer, string and opaque values are stored in ptrs, strs and vals
+// rather than values, so they must only be written through a typed pointer, never copied in as raw bytes
+func (t *ParamTableOf[E]) getBytePtr(idx uint32, typeIdx int) (ptr *byte, subIdx uint32) {
 	subIdx = idx - t.idxOffsets[typeIdx]
-	memOffset := t.byteOffsets[typeIdx] + (uint32(subIdx) * sizeTable[typeIdx])
+	switch typeIdx {
+	case typePtr:
+		return (*byte)(unsafe.Pointer(&t.ptrs[subIdx])), subIdx
+	case typeStr:
+		return (*byte)(unsafe.Pointer(&t.strs[subIdx])), subIdx
+	case typeVal:
+		return (*byte)(unsafe.Pointer(&t.vals[subIdx])), subIdx
+	}
+	memOffset := t.byteOffsets[typeIdx] + (subIdx * sizeTable[typeIdx])
 	return &t.values[memOffset], subIdx
 }
 
-func (t *ParamTable) Get_U8(idx PIdx_U8) uint8 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Uint8", typeU8, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeU8)
-	return *memPtr
+// Returns the type region idx lies in. idx must be inside the parameter list
+func (t *ParamTableOf[E]) typeOfIdx(idx uint32) int {
+	return typeOfIdxIn(&t.idxOffsets, idx)
 }
 
-func (t *ParamTable) Get_I8(idx PIdx_I8) int8 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Int8", typeI8, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeI8)
-	return *(*int8)(unsafe.Pointer(memPtr))
+func typeOfIdxIn(idxOffsets *[typeCount]uint32, idx uint32) int {
+	for typeIdx := 0; typeIdx < typeCount-1; typeIdx += 1 {
+		if idx < idxOffsets[typeIdx+1] {
+			return typeIdx
+		}
+	}
+	return typeBool
 }
 
-func (t *ParamTable) Get_Bool(idx PIdx_Bool) bool {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Bool", typeBool, true, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeBool)
-	return *(*bool)(unsafe.Pointer(memPtr))
+func (t *ParamTableOf[E]) Get_U8(idx PIdx_U8) uint8 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) Get_U16(idx PIdx_U16) uint16 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Uint16", typeU16, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeU16)
-	return *(*uint16)(unsafe.Pointer(memPtr))
-}
-
-func (t *ParamTable) Get_I16(idx PIdx_I16) int16 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Int16", typeI16, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeI16)
-	return *(*int16)(unsafe.Pointer(memPtr))
+func (t *ParamTableOf[E]) Get_I8(idx PIdx_I8) int8 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) Get_U32(idx PIdx_U32) uint32 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Uint32", typeU32, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeU32)
-	return *(*uint32)(unsafe.Pointer(memPtr))
-}
-
-func (t *ParamTable) Get_I32(idx PIdx_I32) int32 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Int32", typeI32, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeI32)
-	return *(*int32)(unsafe.Pointer(memPtr))
-}
-
-func (t *ParamTable) Get_F32(idx PIdx_F32) float32 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Float32", typeF32, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeF32)
-	return *(*float32)(unsafe.Pointer(memPtr))
-}
-
-func (t *ParamTable) Get_U64(idx PIdx_U64) uint64 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Uint64", typeU64, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeU64)
-	return *(*uint64)(unsafe.Pointer(memPtr))
-}
-
-func (t *ParamTable) Get_I64(idx PIdx_I64) int64 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Int64", typeI64, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeI64)
-	return *(*int64)(unsafe.Pointer(memPtr))
-}
-
-func (t *ParamTable) Get_F64(idx PIdx_F64) float64 {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "Float64", typeF64, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typeF64)
-	return *(*float64)(unsafe.Pointer(memPtr))
-}
-
-func (t *ParamTable) Get_Ptr(idx PIdx_Ptr) unsafe.Pointer {
-	_idx := uint16(idx)
-	t.checkIdxType(_idx, "unsafe.Pointer", typePtr, false, true)
-	t.checkInit(_idx)
-	memPtr, _ := t.getBytePtr(_idx, typePtr)
-	return unsafe.Pointer(memPtr)
-}
-
-func (t *ParamTable) set_U8(idx uint16, val uint8, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Uint8", typeU8, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeU8)
-	oldVal := *memPtr
-	*memPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_I8(idx uint16, val int8, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Int8", typeI8, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeI8)
-	valPtr := (*int8)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_Bool(idx uint16, val bool, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Bool", typeBool, true, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeBool)
-	valPtr := (*bool)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_U16(idx uint16, val uint16, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Uint16", typeU16, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeU16)
-	valPtr := (*uint16)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_I16(idx uint16, val int16, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Int16", typeI16, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeI16)
-	valPtr := (*int16)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_U32(idx uint16, val uint32, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Uint32", typeU32, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeU32)
-	valPtr := (*uint32)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_I32(idx uint16, val int32, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Int32", typeI32, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeI32)
-	valPtr := (*int32)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_F32(idx uint16, val float32, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Float32", typeF32, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeF32)
-	valPtr := (*float32)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_U64(idx uint16, val uint64, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Uint64", typeU64, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeU64)
-	valPtr := (*uint64)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
-}
-
-func (t *ParamTable) set_I64(idx uint16, val int64, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Int64", typeI64, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeI64)
-	valPtr := (*int64)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
+func (t *ParamTableOf[E]) Get_Bool(idx PIdx_Bool) bool {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) set_F64(idx uint16, val float64, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "Float64", typeF64, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typeF64)
-	valPtr := (*float64)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
+func (t *ParamTableOf[E]) Get_U16(idx PIdx_U16) uint16 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) set_Ptr(idx uint16, val unsafe.Pointer, canBeDerived bool, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	t.checkIdxType(idx, "unsafe.Pointer", typePtr, false, canBeDerived)
-	f := getFlag(idx, t.flags)
-	memPtr, _ := t.getBytePtr(idx, typePtr)
-	valPtr := (*unsafe.Pointer)(unsafe.Pointer(memPtr))
-	oldVal := *valPtr
-	*valPtr = val
-	newPrevIdxs = prevIdxs
-	if f.AlwaysUpdate() || oldVal != val {
-		newPrevIdxs = t.updateChildren(idx, newPrevIdxs)
-	}
-	return
+func (t *ParamTableOf[E]) Get_I16(idx PIdx_I16) int16 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_U8(idx PIdx_U8, val uint8) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.checkInit(_idx)
-	t.set_U8(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_U32(idx PIdx_U32) uint32 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_I8(idx PIdx_I8, val int8) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.checkInit(_idx)
-	t.set_I8(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_I32(idx PIdx_I32) int32 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_Bool(idx PIdx_Bool, val bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.checkInit(_idx)
-	t.set_Bool(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_F32(idx PIdx_F32) float32 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_U16(idx PIdx_U16, val uint16) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.checkInit(_idx)
-	t.set_U16(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_U64(idx PIdx_U64) uint64 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_I16(idx PIdx_I16, val int16) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.checkInit(_idx)
-	t.set_I16(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_I64(idx PIdx_I64) int64 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_U32(idx PIdx_U32, val uint32) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.set_U32(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_F64(idx PIdx_F64) float64 {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_I32(idx PIdx_I32, val int32) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.set_I32(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_Ptr(idx PIdx_Ptr) unsafe.Pointer {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_F32(idx PIdx_F32, val float32) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.set_F32(_idx, val, false, prev)
+func (t *ParamTableOf[E]) Get_Str(idx PIdx_Str) string {
+	return Get(t, idx)
 }
 
-func (t *ParamTable) SetRoot_U64(idx PIdx_U64, val uint64) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.set_U64(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_U8(idx PIdx_U8, val uint8) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) SetRoot_I64(idx PIdx_I64, val int64) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.set_I64(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_I8(idx PIdx_I8, val int8) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) SetRoot_F64(idx PIdx_F64, val float64) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.set_F64(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_Bool(idx PIdx_Bool, val bool) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) SetRoot_Ptr(idx PIdx_Ptr, val unsafe.Pointer) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	t.set_Ptr(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_U16(idx PIdx_U16, val uint16) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_U8(idx PIdx_U8, val uint8, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_U8(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_I16(idx PIdx_I16, val int16) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_I8(idx PIdx_I8, val int8, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_I8(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_U32(idx PIdx_U32, val uint32) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_Bool(idx PIdx_Bool, val bool, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_Bool(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_I32(idx PIdx_I32, val int32) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_U16(idx PIdx_U16, val uint16, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_U16(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_F32(idx PIdx_F32, val float32) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_I16(idx PIdx_I16, val int16, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_I16(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_U64(idx PIdx_U64, val uint64) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_U32(idx PIdx_U32, val uint32, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_U32(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_I64(idx PIdx_I64, val int64) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_I32(idx PIdx_I32, val int32, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_I32(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_F64(idx PIdx_F64, val float64) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_F32(idx PIdx_F32, val float32, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_F32(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_Ptr(idx PIdx_Ptr, val unsafe.Pointer) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_U64(idx PIdx_U64, val uint64, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_U64(_idx, val, false, prev)
+func (t *ParamTableOf[E]) SetRoot_Str(idx PIdx_Str, val string) {
+	Set(t, idx, val)
 }
 
-func (t *ParamTable) InitRoot_I64(idx PIdx_I64, val int64, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_I64(_idx, val, false, prev)
+func (t *ParamTableOf[E]) InitRoot_U8(idx PIdx_U8, val uint8, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
 }
 
-func (t *ParamTable) InitRoot_F64(idx PIdx_F64, val float64, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_F64(_idx, val, false, prev)
+func (t *ParamTableOf[E]) InitRoot_I8(idx PIdx_I8, val int8, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
 }
 
-func (t *ParamTable) InitRoot_Ptr(idx PIdx_F64, val unsafe.Pointer, alwaysUpdate bool) {
-	_idx := uint16(idx)
-	prev := []uint16{_idx}
-	f := _PFLAG_INIT
-	if alwaysUpdate {
-		f |= _PFLAG_ALWAYS_UPDATE
-	}
-	setFlag(_idx, t.flags, f)
-	t.set_Ptr(_idx, val, false, prev)
+func (t *ParamTableOf[E]) InitRoot_Bool(idx PIdx_Bool, val bool, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_U16(idx PIdx_U16, val uint16, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_I16(idx PIdx_I16, val int16, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_U32(idx PIdx_U32, val uint32, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_I32(idx PIdx_I32, val int32, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_F32(idx PIdx_F32, val float32, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_U64(idx PIdx_U64, val uint64, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
 }
 
-func (t *ParamTable) initHookup(idx uint16, calcIdx PIdx_Calc, parents []uint16, outputs []uint16) {
+func (t *ParamTableOf[E]) InitRoot_I64(idx PIdx_I64, val int64, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_F64(idx PIdx_F64, val float64, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_Ptr(idx PIdx_Ptr, val unsafe.Pointer, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) InitRoot_Str(idx PIdx_Str, val string, alwaysUpdate bool) {
+	InitRoot(t, idx, val, alwaysUpdate)
+}
+
+func (t *ParamTableOf[E]) initHookup(idx E, calcIdx PIdx_Calc, parents []E, outputs []E) {
 	hookStart := uint32(len(t.hookupData))
-	if EnableDebug {
-		if len(parents) > 255 {
-			fmt.Fprintf(DebugWriter, "fatal: go_param_table: derived values can only have a maximum of 255 parents (calculation inputs), got parent len %d", len(parents))
-			panic(1)
-		}
-		if len(outputs) > 255 {
-			fmt.Fprintf(DebugWriter, "fatal: go_param_table: derived values can only have a maximum of 255 calculation outputs, got output len %d", len(outputs))
-			panic(1)
-		}
-	}
 	inLen := uint32(len(parents))
 	outLen := uint32(len(outputs))
 	hookLen := _HOOK_OFF_INSTART + inLen + outLen
-	paramsLen := newParamsLen(inLen, outLen)
+	paramsLen := newParamsLen[E](inLen, outLen)
 	t.hookupData = slices.Grow(t.hookupData, int(hookLen))
-	t.hookupData = append(t.hookupData, uint16(calcIdx), uint16(paramsLen), 0)
+	t.hookupData = append(t.hookupData, E(calcIdx), paramsLen, 0, 0)
 	t.hookupData = append(t.hookupData, parents...)
 	t.hookupData = append(t.hookupData, outputs...)
 	t.hookups[idx] = hookup(hookStart)
 }
 
-func (t *ParamTable) initRootHookupWithChild(rootIdx uint16, childIdx uint16) {
+func (t *ParamTableOf[E]) initRootHookupWithChild(rootIdx E, childIdx E) {
 	hookStart := uint32(len(t.hookupData))
 	hookLen := _HOOK_OFF_INSTART + 1
 	t.hookupData = slices.Grow(t.hookupData, int(hookLen))
-	t.hookupData = append(t.hookupData, 0, 0, 1, childIdx)
+	t.hookupData = append(t.hookupData, 0, 0, 1, 1, childIdx)
 	t.hookups[rootIdx] = hookup(hookStart)
 }
 
-func (t *ParamTable) initDerivedHookups(idx uint16, alwaysUpdate bool, calcIdx PIdx_Calc, parents []uint16, outputs []uint16) {
+func (t *ParamTableOf[E]) initDerivedHookups(idx E, alwaysUpdate bool, calcIdx PIdx_Calc, parents []E, outputs []E) {
+	if err := t.hookupErr(idx, calcIdx, parents, outputs, t.debug.enabled); err != nil {
+		t.fail(err)
+	}
 	f := _PFLAG_INIT
 	if alwaysUpdate {
 		f |= _PFLAG_ALWAYS_UPDATE
 	}
-	setFlag(idx, t.flags, f)
+	setFlag(uint32(idx), t.flags, f)
+	t.structure = nextStructureID()
 	t.initHookup(idx, calcIdx, parents, outputs)
 	for _, parent := range parents {
 		t.addChild(parent, idx)
 	}
-	prevIdxs := t.trigger(idx, []uint16{idx})
-	t.updateChildren(idx, prevIdxs)
+	t.trigger(idx)
+	t.propagateFrom(idx)
 }
 
-func (t *ParamTable) getCalc(calcIdx PIdx_Calc) ParamCalc {
-	if EnableDebug {
-		if t.calcs[calcIdx] == nil {
-			fmt.Fprintf(DebugWriter, "fatal: go_param_table: calc index %d has not been registered", calcIdx)
-			panic(1)
+// Validates the hookup of a new derived value. Cycles are always checked, everything else only
+// when full is true
+func (t *ParamTableOf[E]) hookupErr(idx E, calcIdx PIdx_Calc, parents []E, outputs []E, full bool) error {
+	if full {
+		maxLen := maxParamsLen[E]()
+		if len(parents) > maxLen {
+			return newParamError(ErrTooManyHookups, idx, "derived values can only have a maximum of %d parents (calculation inputs), got parent len %d (derived value %s)", maxLen, len(parents), t.paramLabel(uint32(idx)))
+		}
+		if len(outputs) > maxLen {
+			return newParamError(ErrTooManyHookups, idx, "derived values can only have a maximum of %d calculation outputs, got output len %d (derived value %s)", maxLen, len(outputs), t.paramLabel(uint32(idx)))
+		}
+		if err := t.calcErr(calcIdx); err != nil {
+			return err
+		}
+		for _, parent := range parents {
+			if parent >= E(len(t.hookups)) {
+				return newParamError(ErrIndexOutOfRange, parent, "parent index %d of derived value %s is outside bounds of parameter list (len %d)", parent, t.paramLabel(uint32(idx)), len(t.hookups))
+			}
+		}
+		for _, output := range outputs {
+			if output >= E(len(t.hookups)) {
+				return newParamError(ErrIndexOutOfRange, output, "output index %d of derived value %s is outside bounds of parameter list (len %d)", output, t.paramLabel(uint32(idx)), len(t.hookups))
+			}
+		}
+		for _, parent := range parents {
+			if t.childCount(parent) >= maxChildren[E]() && !slices.Contains(t.getChildren(parent), idx) {
+				return newParamError(ErrTooManyChildren, parent, "index %s already has the maximum of %d children, cannot add derived value %s", t.paramLabel(uint32(parent)), maxChildren[E](), t.paramLabel(uint32(idx)))
+			}
 		}
 	}
-	return t.calcs[calcIdx]
+	if err := t.cycleErr(idx, parents, outputs); err != nil {
+		return err
+	}
+	if full {
+		for _, parent := range parents {
+			if err := t.initErr(uint32(parent)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-func (t *ParamTable) RegisterCalc(calcIdx PIdx_Calc, calc ParamCalc) {
-	if EnableDebug {
-		if calcIdx > PIdx_Calc(len(t.calcs)) {
-			fmt.Fprintf(DebugWriter, "fatal: go_param_table: calc index %d is outside bounds of calc list (len %d)", calcIdx, uint16(len(t.calcs)))
-			panic(1)
+func (t *ParamTableOf[E]) getCalc(calcIdx PIdx_Calc) ParamCalcOf[E] {
+	if t.debug.enabled {
+		if err := t.calcErr(calcIdx); err != nil {
+			t.fail(err)
 		}
-		if t.calcs[calcIdx] != nil {
-			fmt.Fprintf(DebugWriter, "fatal: go_param_table: calc index %d is already registered", calcIdx)
-			panic(1)
+	}
+	return t.calcs[calcIdx]
+}
+
+func (t *ParamTableOf[E]) calcErr(calcIdx PIdx_Calc) error {
+	if int(calcIdx) >= len(t.calcs) {
+		return newParamError(ErrCalcOutOfRange, uint32(calcIdx), "calc index %d is outside bounds of calc list (len %d)", calcIdx, len(t.calcs))
+	}
+	if t.calcs[calcIdx] == nil {
+		return newParamError(ErrCalcNotRegistered, uint32(calcIdx), "calc index %s has not been registered", t.calcLabel(calcIdx))
+	}
+	return nil
+}
+
+func (t *ParamTableOf[E]) RegisterCalc(calcIdx PIdx_Calc, calc ParamCalcOf[E], opts ...CalcOption) {
+	if t.debug.enabled {
+		if err := t.registerErr(calcIdx); err != nil {
+			t.fail(err)
 		}
 	}
 	t.calcs[calcIdx] = calc
+	var config calcConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	t.setCalcName(calcIdx, config.name)
+	t.setCalcParallel(calcIdx, config.parallel)
+}
+
+func (t *ParamTableOf[E]) registerErr(calcIdx PIdx_Calc) error {
+	if int(calcIdx) >= len(t.calcs) {
+		return newParamError(ErrCalcOutOfRange, uint32(calcIdx), "calc index %d is outside bounds of calc list (len %d)", calcIdx, len(t.calcs))
+	}
+	if t.calcs[calcIdx] != nil {
+		return newParamError(ErrCalcAlreadyRegistered, uint32(calcIdx), "calc index %s is already registered", t.calcLabel(calcIdx))
+	}
+	return nil
+}
+
+func (t *ParamTableOf[E]) InitDerived_U8(idx PIdx_U8, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Uint8", typeU8, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
+}
+
+func (t *ParamTableOf[E]) InitDerived_I8(idx PIdx_I8, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Int8", typeI8, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_U8(idx PIdx_U8, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Uint8", typeU8, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_Bool(idx PIdx_Bool, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Bool", typeBool, true, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_I8(idx PIdx_I8, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Int8", typeI8, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_U16(idx PIdx_U16, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Uint16", typeU16, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_Bool(idx PIdx_Bool, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Bool", typeBool, true, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_I16(idx PIdx_I16, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Int16", typeI16, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_U16(idx PIdx_U16, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Uint16", typeU16, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_U32(idx PIdx_U32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Uint32", typeU32, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_I16(idx PIdx_I16, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Int16", typeI16, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_I32(idx PIdx_I32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Int32", typeI32, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_U32(idx PIdx_U32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Uint32", typeU32, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_F32(idx PIdx_F32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Float32", typeF32, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_I32(idx PIdx_I32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Int32", typeI32, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_U64(idx PIdx_U64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Uint64", typeU64, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_F32(idx PIdx_F32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Float32", typeF32, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_I64(idx PIdx_I64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Int64", typeI64, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_U64(idx PIdx_U64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Uint64", typeU64, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_F64(idx PIdx_F64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "Float64", typeF64, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_I64(idx PIdx_I64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Int64", typeI64, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_Ptr(idx PIdx_Ptr, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "unsafe.Pointer", typePtr, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_F64(idx PIdx_F64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Float64", typeF64, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+func (t *ParamTableOf[E]) InitDerived_Str(idx PIdx_Str, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.checkIdxType(uint32(idx), "String", typeStr, false, true)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) InitDerived_Addr(idx PIdx_Ptr, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []uint16, outputs []uint16) {
-	t.checkIdxType(uint16(idx), "Uintptr", typePtr, false, true)
-	t.initDerivedHookups(uint16(idx), alwaysUpdate, calcIdx, inputs, outputs)
+// Deprecated: use InitDerived_Ptr
+func (t *ParamTableOf[E]) InitDerived_Addr(idx PIdx_Ptr, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	t.InitDerived_Ptr(idx, alwaysUpdate, calcIdx, inputs, outputs)
 }
 
-func (t *ParamTable) updateChildren(idx uint16, prevIdxs []uint16) (newPrevIdxs []uint16) {
-	newPrevIdxs = prevIdxs
-	children := t.getChildren(idx)
-	if len(children) == 0 {
+// The view of the table a calc gets while it runs: its inputs and outputs are addressed by their
+// position in the input and output lists the derived value was initialized with
+type CalcInterfaceOf[E Index] struct {
+	table   *ParamTableOf[E]
+	inputs  []E
+	outputs []E
+	// set when the calc runs in parallel, collects the changed outputs instead of marking them
+	changed *[]E
+}
+
+// Records that an output changed, see ParamTableOf.markChanged()
+func (c *CalcInterfaceOf[E]) markChanged(idx E) {
+	if c.changed != nil {
+		*c.changed = append(*c.changed, idx)
 		return
 	}
-	for _, child := range children {
-		if EnableDebug {
-			for _, prevIdx := range prevIdxs {
-				if child == prevIdx {
-					fmt.Fprintf(DebugWriter, "fatal: go_param_table: cyclic update loop: during update, idx %d was updated higher (previous) in the heirarchy, but idx %d had previous idx %d as a child, creating an infinite loop", prevIdx, idx, prevIdx)
-					panic(1)
-				}
-			}
-			newPrevIdxs = append(newPrevIdxs, child)
-		}
-		newPrevIdxs = t.trigger(child, newPrevIdxs)
-	}
-	return
+	c.table.markChanged(idx)
 }
 
-type CalcInterface struct {
-	table    *ParamTable
-	inputs   []uint16
-	outputs  []uint16
-	prevIdxs []uint16
-}
+type (
+	CalcInterface     = CalcInterfaceOf[uint16]
+	WideCalcInterface = CalcInterfaceOf[uint32]
+)
 
-func (t CalcInterface) GetInput_U8(inputIdx uint16) uint8 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_U8(PIdx_U8(idx))
+func (t CalcInterfaceOf[E]) GetInput_U8(inputIdx uint16) uint8 {
+	return Input[uint8](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_I8(inputIdx uint16) int8 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_I8(PIdx_I8(idx))
+func (t CalcInterfaceOf[E]) GetInput_I8(inputIdx uint16) int8 {
+	return Input[int8](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_Bool(inputIdx uint16) bool {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_Bool(PIdx_Bool(idx))
+func (t CalcInterfaceOf[E]) GetInput_Bool(inputIdx uint16) bool {
+	return Input[bool](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_U16(inputIdx uint16) uint16 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_U16(PIdx_U16(idx))
+func (t CalcInterfaceOf[E]) GetInput_U16(inputIdx uint16) uint16 {
+	return Input[uint16](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_I16(inputIdx uint16) int16 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_I16(PIdx_I16(idx))
+func (t CalcInterfaceOf[E]) GetInput_I16(inputIdx uint16) int16 {
+	return Input[int16](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_U32(inputIdx uint16) uint32 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_U32(PIdx_U32(idx))
+func (t CalcInterfaceOf[E]) GetInput_U32(inputIdx uint16) uint32 {
+	return Input[uint32](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_I32(inputIdx uint16) int32 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_I32(PIdx_I32(idx))
+func (t CalcInterfaceOf[E]) GetInput_I32(inputIdx uint16) int32 {
+	return Input[int32](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_F32(inputIdx uint16) float32 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_F32(PIdx_F32(idx))
+func (t CalcInterfaceOf[E]) GetInput_F32(inputIdx uint16) float32 {
+	return Input[float32](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_U64(inputIdx uint16) uint64 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_U64(PIdx_U64(idx))
+func (t CalcInterfaceOf[E]) GetInput_U64(inputIdx uint16) uint64 {
+	return Input[uint64](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_I64(inputIdx uint16) int64 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_I64(PIdx_I64(idx))
+func (t CalcInterfaceOf[E]) GetInput_I64(inputIdx uint16) int64 {
+	return Input[int64](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_F64(inputIdx uint16) float64 {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_F64(PIdx_F64(idx))
+func (t CalcInterfaceOf[E]) GetInput_F64(inputIdx uint16) float64 {
+	return Input[float64](&t, inputIdx)
 }
-func (t CalcInterface) GetInput_Ptr(inputIdx uint16) unsafe.Pointer {
-	idx := t.inputs[inputIdx]
-	return t.table.Get_Ptr(PIdx_Ptr(idx))
+func (t CalcInterfaceOf[E]) GetInput_Ptr(inputIdx uint16) unsafe.Pointer {
+	return Input[unsafe.Pointer](&t, inputIdx)
 }
-func (t CalcInterface) GetAllInputs() []uint16 {
+func (t CalcInterfaceOf[E]) GetInput_Str(inputIdx uint16) string {
+	return Input[string](&t, inputIdx)
+}
+func (t CalcInterfaceOf[E]) GetAllInputs() []E {
 	return t.inputs
 }
-func (t CalcInterface) GetInputRangeStart(start uint16) []uint16 {
+func (t CalcInterfaceOf[E]) GetInputRangeStart(start uint16) []E {
 	return t.inputs[start:]
 }
-func (t CalcInterface) GetInputRangeEnd(end uint16) []uint16 {
+func (t CalcInterfaceOf[E]) GetInputRangeEnd(end uint16) []E {
 	return t.inputs[:end]
 }
-func (t CalcInterface) GetInputRangeStartEnd(start, end uint16) []uint16 {
+func (t CalcInterfaceOf[E]) GetInputRangeStartEnd(start, end uint16) []E {
 	return t.inputs[start:end]
 }
 
-func (t *CalcInterface) SetOutput_U8(outputIdx uint16, val uint8) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_U8(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_U8(outputIdx uint16, val uint8) {
+	Output(t, outputIdx, val)
+}
+func (t *CalcInterfaceOf[E]) SetOutput_I8(outputIdx uint16, val int8) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_I8(outputIdx uint16, val int8) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_I8(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_Bool(outputIdx uint16, val bool) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_Bool(outputIdx uint16, val bool) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_Bool(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_U16(outputIdx uint16, val uint16) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_U16(outputIdx uint16, val uint16) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_U16(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_I16(outputIdx uint16, val int16) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_I16(outputIdx uint16, val int16) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_I16(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_U32(outputIdx uint16, val uint32) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_U32(outputIdx uint16, val uint32) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_U32(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_I32(outputIdx uint16, val int32) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_I32(outputIdx uint16, val int32) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_I32(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_F32(outputIdx uint16, val float32) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_F32(outputIdx uint16, val float32) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_F32(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_U64(outputIdx uint16, val uint64) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_U64(outputIdx uint16, val uint64) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_U64(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_I64(outputIdx uint16, val int64) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_I64(outputIdx uint16, val int64) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_I64(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_F64(outputIdx uint16, val float64) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_F64(outputIdx uint16, val float64) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_F64(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_Ptr(outputIdx uint16, val unsafe.Pointer) {
+	Output(t, outputIdx, val)
 }
-func (t *CalcInterface) SetOutput_Ptr(outputIdx uint16, val unsafe.Pointer) {
-	idx := t.outputs[outputIdx]
-	t.prevIdxs = t.table.set_Ptr(idx, val, true, t.prevIdxs)
+func (t *CalcInterfaceOf[E]) SetOutput_Str(outputIdx uint16, val string) {
+	Output(t, outputIdx, val)
 }
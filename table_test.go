@@ -5,7 +5,11 @@ import (
 )
 
 func TestParamTable(t *testing.T) {
-	EnableDebug = true
+	t.Run("narrow", testParamTable[uint16])
+	t.Run("wide", testParamTable[uint32])
+}
+
+func testParamTable[E Index](t *testing.T) {
 	const (
 		FIRST_U64_PARAM       PIdx_U64 = PIdx_U64(iota)
 		RECT_WIDTH_1                   // example root val
@@ -36,13 +40,25 @@ func TestParamTable(t *testing.T) {
 	)
 
 	const (
-		FIRST_PTR_PARAM PIdx_Ptr = PIdx_Ptr(iota + _I64_PARAMS_END)
-		// ... more float64 param indexes
+		FIRST_PTR_PARAM PIdx_Ptr = PIdx_Ptr(iota + _F64_PARAMS_END)
+		// ... more unsafe.Pointer param indexes
 		_PTR_PARAMS_END
 	)
 
 	const (
-		FIRST_U32_PARAM PIdx_U32 = PIdx_U32(iota + _PTR_PARAMS_END)
+		FIRST_STR_PARAM PIdx_Str = PIdx_Str(iota + _PTR_PARAMS_END)
+		// ... more string param indexes
+		_STR_PARAMS_END
+	)
+
+	const (
+		FIRST_VAL_PARAM PIdx_Val[[]byte] = PIdx_Val[[]byte](iota + _STR_PARAMS_END)
+		// ... more opaque value param indexes, each declared with its own value type
+		_VAL_PARAMS_END PIdx_Val[any] = PIdx_Val[any](iota + _STR_PARAMS_END)
+	)
+
+	const (
+		FIRST_U32_PARAM PIdx_U32 = PIdx_U32(iota + _VAL_PARAMS_END)
 		RECT_DEPTH_1             // example root val
 		RECT_DEPTH_2             // example root val
 		// ... more uint32 param indexes
@@ -108,31 +124,31 @@ func TestParamTable(t *testing.T) {
 		_OUT_AREA_OF_RECTANGLE_AREA uint16 = iota
 	)
 
-	var _INS_AREA_OF_RECTANGLE_1 = [...]uint16{
-		_IN_AREA_OF_RECTANGLE_WIDTH:  uint16(RECT_WIDTH_1),
-		_IN_AREA_OF_RECTANGLE_HEIGHT: uint16(RECT_HEIGHT_1),
+	var _INS_AREA_OF_RECTANGLE_1 = [...]E{
+		_IN_AREA_OF_RECTANGLE_WIDTH:  E(RECT_WIDTH_1),
+		_IN_AREA_OF_RECTANGLE_HEIGHT: E(RECT_HEIGHT_1),
 	}
-	var _OUTS_AREA_OF_RECTANGLE_1 = [...]uint16{
-		_OUT_AREA_OF_RECTANGLE_AREA: uint16(RECT_AREA_1),
+	var _OUTS_AREA_OF_RECTANGLE_1 = [...]E{
+		_OUT_AREA_OF_RECTANGLE_AREA: E(RECT_AREA_1),
 	}
-	var _OUTS_AREA_OF_RECTANGLE_1_DUPE = [...]uint16{
-		_OUT_AREA_OF_RECTANGLE_AREA: uint16(RECT_AREA_DUPLICATE_1),
+	var _OUTS_AREA_OF_RECTANGLE_1_DUPE = [...]E{
+		_OUT_AREA_OF_RECTANGLE_AREA: E(RECT_AREA_DUPLICATE_1),
 	}
 
-	var _INS_AREA_OF_RECTANGLE_2 = [...]uint16{
-		_IN_AREA_OF_RECTANGLE_WIDTH:  uint16(RECT_WIDTH_2),
-		_IN_AREA_OF_RECTANGLE_HEIGHT: uint16(RECT_HEIGHT_2),
+	var _INS_AREA_OF_RECTANGLE_2 = [...]E{
+		_IN_AREA_OF_RECTANGLE_WIDTH:  E(RECT_WIDTH_2),
+		_IN_AREA_OF_RECTANGLE_HEIGHT: E(RECT_HEIGHT_2),
 	}
-	var _OUTS_AREA_OF_RECTANGLE_2 = [...]uint16{
-		_OUT_AREA_OF_RECTANGLE_AREA: uint16(RECT_AREA_2),
+	var _OUTS_AREA_OF_RECTANGLE_2 = [...]E{
+		_OUT_AREA_OF_RECTANGLE_AREA: E(RECT_AREA_2),
 	}
 
-	var _INS_AREA_OF_RECTANGLE_CYCLIC = [...]uint16{
-		_IN_AREA_OF_RECTANGLE_WIDTH:  uint16(RECT_AREA_CYCLIC),
-		_IN_AREA_OF_RECTANGLE_HEIGHT: uint16(RECT_HEIGHT_2),
+	var _INS_AREA_OF_RECTANGLE_CYCLIC = [...]E{
+		_IN_AREA_OF_RECTANGLE_WIDTH:  E(RECT_AREA_CYCLIC),
+		_IN_AREA_OF_RECTANGLE_HEIGHT: E(RECT_HEIGHT_2),
 	}
-	var _OUTS_AREA_OF_RECTANGLE_CYCLIC = [...]uint16{
-		_OUT_AREA_OF_RECTANGLE_AREA: uint16(RECT_AREA_CYCLIC),
+	var _OUTS_AREA_OF_RECTANGLE_CYCLIC = [...]E{
+		_OUT_AREA_OF_RECTANGLE_AREA: E(RECT_AREA_CYCLIC),
 	}
 
 	const (
@@ -144,20 +160,20 @@ func TestParamTable(t *testing.T) {
 		_OUT_VOLUME_OF_RECTANGLE_VOLUME uint16 = iota
 	)
 
-	var _INS_VOLUME_OF_RECTANGLE_1 = [...]uint16{
-		_IN_VOLUME_OF_RECTANGLE_AREA:  uint16(RECT_AREA_1),
-		_IN_VOLUME_OF_RECTANGLE_DEPTH: uint16(RECT_DEPTH_1),
+	var _INS_VOLUME_OF_RECTANGLE_1 = [...]E{
+		_IN_VOLUME_OF_RECTANGLE_AREA:  E(RECT_AREA_1),
+		_IN_VOLUME_OF_RECTANGLE_DEPTH: E(RECT_DEPTH_1),
 	}
-	var _OUTS_VOLUME_OF_RECTANGLE_1 = [...]uint16{
-		_OUT_VOLUME_OF_RECTANGLE_VOLUME: uint16(RECT_VOLUME_1),
+	var _OUTS_VOLUME_OF_RECTANGLE_1 = [...]E{
+		_OUT_VOLUME_OF_RECTANGLE_VOLUME: E(RECT_VOLUME_1),
 	}
 
-	var _INS_VOLUME_OF_RECTANGLE_2 = [...]uint16{
-		_IN_VOLUME_OF_RECTANGLE_AREA:  uint16(RECT_AREA_2),
-		_IN_VOLUME_OF_RECTANGLE_DEPTH: uint16(RECT_DEPTH_2),
+	var _INS_VOLUME_OF_RECTANGLE_2 = [...]E{
+		_IN_VOLUME_OF_RECTANGLE_AREA:  E(RECT_AREA_2),
+		_IN_VOLUME_OF_RECTANGLE_DEPTH: E(RECT_DEPTH_2),
 	}
-	var _OUTS_VOLUME_OF_RECTANGLE_2 = [...]uint16{
-		_OUT_VOLUME_OF_RECTANGLE_VOLUME: uint16(RECT_VOLUME_2),
+	var _OUTS_VOLUME_OF_RECTANGLE_2 = [...]E{
+		_OUT_VOLUME_OF_RECTANGLE_VOLUME: E(RECT_VOLUME_2),
 	}
 
 	const (
@@ -169,32 +185,47 @@ func TestParamTable(t *testing.T) {
 		_OUT_SUM_OF_RECT_VOLUME_SUM uint16 = iota
 	)
 
-	var _INS_SUM_OF_RECT_VOLUMES_1_2 = [...]uint16{
-		_IN_SUM_OF_RECT_VOLUME_1: uint16(RECT_VOLUME_1),
-		_IN_SUM_OF_RECT_VOLUME_2: uint16(RECT_VOLUME_2),
+	var _INS_SUM_OF_RECT_VOLUMES_1_2 = [...]E{
+		_IN_SUM_OF_RECT_VOLUME_1: E(RECT_VOLUME_1),
+		_IN_SUM_OF_RECT_VOLUME_2: E(RECT_VOLUME_2),
 	}
-	var _OUTS_SUM_OF_RECT_VOLUMES_1_2 = [...]uint16{
-		_OUT_SUM_OF_RECT_VOLUME_SUM: uint16(RECT_SUM_VOLUME),
+	var _OUTS_SUM_OF_RECT_VOLUMES_1_2 = [...]E{
+		_OUT_SUM_OF_RECT_VOLUME_SUM: E(RECT_SUM_VOLUME),
 	}
 
-	var tooLongHookup [277]uint16
+	var tooLongHookup = make([]E, maxParamsLen[E]()+22)
 
-	var MyParamTable = NewParamTable(_U64_PARAMS_END, _I64_PARAMS_END, _F64_PARAMS_END, _PTR_PARAMS_END, _U32_PARAMS_END, _I32_PARAMS_END, _F32_PARAMS_END, _U16_PARAMS_END, _I16_PARAMS_END, _U8_PARAMS_END, _I8_PARAMS_END, _BOOL_PARAMS_END, _CALC_COUNT)
+	var MyParamTable = newTestTableOf[E](ParamLayout{
+		U64End:  _U64_PARAMS_END,
+		I64End:  _I64_PARAMS_END,
+		F64End:  _F64_PARAMS_END,
+		PtrEnd:  _PTR_PARAMS_END,
+		StrEnd:  _STR_PARAMS_END,
+		ValEnd:  _VAL_PARAMS_END,
+		U32End:  _U32_PARAMS_END,
+		I32End:  _I32_PARAMS_END,
+		F32End:  _F32_PARAMS_END,
+		U16End:  _U16_PARAMS_END,
+		I16End:  _I16_PARAMS_END,
+		U8End:   _U8_PARAMS_END,
+		I8End:   _I8_PARAMS_END,
+		BoolEnd: _BOOL_PARAMS_END,
+	}, _CALC_COUNT, WithDebug(true))
 	var InitMyParamTable func() = func() {
 		// Register all calculations first
-		MyParamTable.RegisterCalc(_CALC_AREA_OF_RECTANGLE, func(t *CalcInterface) {
+		MyParamTable.RegisterCalc(_CALC_AREA_OF_RECTANGLE, func(t *CalcInterfaceOf[E]) {
 			width := t.GetInput_U64(_IN_AREA_OF_RECTANGLE_WIDTH)   // first input
 			height := t.GetInput_U64(_IN_AREA_OF_RECTANGLE_HEIGHT) // second input
 			area := width * height
 			t.SetOutput_U64(_OUT_AREA_OF_RECTANGLE_AREA, area)
 		})
-		MyParamTable.RegisterCalc(_CALC_VOLUME_OF_RECTANGLE, func(t *CalcInterface) {
+		MyParamTable.RegisterCalc(_CALC_VOLUME_OF_RECTANGLE, func(t *CalcInterfaceOf[E]) {
 			area := t.GetInput_U64(_IN_VOLUME_OF_RECTANGLE_AREA)   // first input
 			depth := t.GetInput_U32(_IN_VOLUME_OF_RECTANGLE_DEPTH) // second input
 			volume := area * uint64(depth)
 			t.SetOutput_U64(_OUT_VOLUME_OF_RECTANGLE_VOLUME, volume)
 		})
-		MyParamTable.RegisterCalc(_CALC_SUM_OF_VOLUME_OF_RECTS, func(t *CalcInterface) {
+		MyParamTable.RegisterCalc(_CALC_SUM_OF_VOLUME_OF_RECTS, func(t *CalcInterfaceOf[E]) {
 			vol1 := t.GetInput_U64(_IN_SUM_OF_RECT_VOLUME_1) // first input
 			vol2 := t.GetInput_U64(_IN_SUM_OF_RECT_VOLUME_2) // second input
 			sumVol := vol1 + vol2
@@ -302,7 +333,7 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("setting root value with wrong type did not cause panic with EnableDebug == true")
+				t.Errorf("setting root value with wrong type did not cause panic with debug checks enabled")
 			}
 		}()
 		MyParamTable.SetRoot_Bool(PIdx_Bool(RECT_HEIGHT_1), true)
@@ -310,7 +341,7 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("setting root value with idx out of range did not cause panic with EnableDebug == true")
+				t.Errorf("setting root value with idx out of range did not cause panic with debug checks enabled")
 			}
 		}()
 		MyParamTable.SetRoot_Bool(PIdx_Bool(1000), true)
@@ -318,7 +349,7 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("directly setting derived value did not cause panic with EnableDebug == true")
+				t.Errorf("directly setting derived value did not cause panic with debug checks enabled")
 			}
 		}()
 		MyParamTable.SetRoot_U64(RECT_AREA_1, 1)
@@ -326,7 +357,7 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("getting unregistered calc did not cause panic with EnableDebug == true")
+				t.Errorf("getting unregistered calc did not cause panic with debug checks enabled")
 			}
 		}()
 		var _ = MyParamTable.getCalc(_CALC_INVALID)
@@ -334,15 +365,15 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("re-registering already registered calc did not cause panic with EnableDebug == true")
+				t.Errorf("re-registering already registered calc did not cause panic with debug checks enabled")
 			}
 		}()
-		MyParamTable.RegisterCalc(_CALC_AREA_OF_RECTANGLE, func(t *CalcInterface) {})
+		MyParamTable.RegisterCalc(_CALC_AREA_OF_RECTANGLE, func(t *CalcInterfaceOf[E]) {})
 	}()
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("causing derived cyclic loop did not cause panic with EnableDebug == true")
+				t.Errorf("causing derived cyclic loop did not cause panic with debug checks enabled")
 			}
 		}()
 		MyParamTable.InitDerived_U64(RECT_AREA_CYCLIC, false, _CALC_AREA_OF_RECTANGLE, _INS_AREA_OF_RECTANGLE_CYCLIC[:], _OUTS_AREA_OF_RECTANGLE_CYCLIC[:])
@@ -350,15 +381,15 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("out-of-order NewParamTable did not cause panic with EnableDebug == true")
+				t.Errorf("out-of-order layout did not cause panic with debug checks enabled")
 			}
 		}()
-		var _ = NewParamTable(PIdx_U64(_U32_PARAMS_END), _I64_PARAMS_END, _F64_PARAMS_END, _PTR_PARAMS_END, PIdx_U32(_U64_PARAMS_END), _I32_PARAMS_END, _F32_PARAMS_END, _U16_PARAMS_END, _I16_PARAMS_END, _U8_PARAMS_END, _I8_PARAMS_END, _BOOL_PARAMS_END, _CALC_COUNT)
+		var _ = newTestTableOf[E](ParamLayout{U64End: PIdx_U64(_U32_PARAMS_END), U32End: PIdx_U32(_U64_PARAMS_END)}, _CALC_COUNT, WithDebug(true))
 	}()
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("overlong parent length did not cause panic with EnableDebug == true")
+				t.Errorf("overlong parent length did not cause panic with debug checks enabled")
 			}
 		}()
 		MyParamTable.InitDerived_F64(FIRST_F64_PARAM, false, _CALC_AREA_OF_RECTANGLE, tooLongHookup[:], _OUTS_AREA_OF_RECTANGLE_CYCLIC[:])
@@ -366,7 +397,7 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("overlong outputs length did not cause panic with EnableDebug == true")
+				t.Errorf("overlong outputs length did not cause panic with debug checks enabled")
 			}
 		}()
 		MyParamTable.InitDerived_I8(FIRST_I8_PARAM, false, _CALC_AREA_OF_RECTANGLE, _INS_AREA_OF_RECTANGLE_CYCLIC[:], tooLongHookup[:])
@@ -374,7 +405,7 @@ func TestParamTable(t *testing.T) {
 	func() {
 		defer func() {
 			if r := recover(); r == nil {
-				t.Errorf("getting uninit val did not cause panic with EnableDebug == true")
+				t.Errorf("getting uninit val did not cause panic with debug checks enabled")
 			}
 		}()
 		MyParamTable.Get_I16(FIRST_I16_PARAM)
@@ -0,0 +1,480 @@
+package go_param_table
+
+import (
+	"unsafe"
+)
+
+// The Try*() variants below always perform the full set of safety checks, regardless of
+// the table's debug setting, and return a *ParamError instead of panicking. The table is left unmodified
+// when an error is returned. Use the plain variants in hot loops once the table is known good
+
+func (t *ParamTableOf[E]) getErr(idx uint32, name string, validType int, final bool) error {
+	if err := t.idxTypeErr(idx, name, validType, final, true); err != nil {
+		return err
+	}
+	return t.initErr(idx)
+}
+
+func (t *ParamTableOf[E]) setRootErr(idx uint32, name string, validType int, final bool) error {
+	if err := t.idxTypeErr(idx, name, validType, final, false); err != nil {
+		return err
+	}
+	return t.initErr(idx)
+}
+
+func (t *ParamTableOf[E]) initRootErr(idx uint32, name string, validType int, final bool) error {
+	if err := t.idxTypeErr(idx, name, validType, final, false); err != nil {
+		return err
+	}
+	return t.noBatchErr("InitRoot")
+}
+
+func (t *ParamTableOf[E]) TryRegisterCalc(calcIdx PIdx_Calc, calc ParamCalcOf[E], opts ...CalcOption) error {
+	if err := t.registerErr(calcIdx); err != nil {
+		return err
+	}
+	t.RegisterCalc(calcIdx, calc, opts...)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryGet_U8(idx PIdx_U8) (uint8, error) {
+	if err := t.getErr(uint32(idx), "Uint8", typeU8, false); err != nil {
+		return 0, err
+	}
+	return t.Get_U8(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_I8(idx PIdx_I8) (int8, error) {
+	if err := t.getErr(uint32(idx), "Int8", typeI8, false); err != nil {
+		return 0, err
+	}
+	return t.Get_I8(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_Bool(idx PIdx_Bool) (bool, error) {
+	if err := t.getErr(uint32(idx), "Bool", typeBool, true); err != nil {
+		return false, err
+	}
+	return t.Get_Bool(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_U16(idx PIdx_U16) (uint16, error) {
+	if err := t.getErr(uint32(idx), "Uint16", typeU16, false); err != nil {
+		return 0, err
+	}
+	return t.Get_U16(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_I16(idx PIdx_I16) (int16, error) {
+	if err := t.getErr(uint32(idx), "Int16", typeI16, false); err != nil {
+		return 0, err
+	}
+	return t.Get_I16(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_U32(idx PIdx_U32) (uint32, error) {
+	if err := t.getErr(uint32(idx), "Uint32", typeU32, false); err != nil {
+		return 0, err
+	}
+	return t.Get_U32(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_I32(idx PIdx_I32) (int32, error) {
+	if err := t.getErr(uint32(idx), "Int32", typeI32, false); err != nil {
+		return 0, err
+	}
+	return t.Get_I32(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_F32(idx PIdx_F32) (float32, error) {
+	if err := t.getErr(uint32(idx), "Float32", typeF32, false); err != nil {
+		return 0, err
+	}
+	return t.Get_F32(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_U64(idx PIdx_U64) (uint64, error) {
+	if err := t.getErr(uint32(idx), "Uint64", typeU64, false); err != nil {
+		return 0, err
+	}
+	return t.Get_U64(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_I64(idx PIdx_I64) (int64, error) {
+	if err := t.getErr(uint32(idx), "Int64", typeI64, false); err != nil {
+		return 0, err
+	}
+	return t.Get_I64(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_F64(idx PIdx_F64) (float64, error) {
+	if err := t.getErr(uint32(idx), "Float64", typeF64, false); err != nil {
+		return 0, err
+	}
+	return t.Get_F64(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_Ptr(idx PIdx_Ptr) (unsafe.Pointer, error) {
+	if err := t.getErr(uint32(idx), "unsafe.Pointer", typePtr, false); err != nil {
+		return nil, err
+	}
+	return t.Get_Ptr(idx), nil
+}
+
+func (t *ParamTableOf[E]) TryGet_Str(idx PIdx_Str) (string, error) {
+	if err := t.getErr(uint32(idx), "String", typeStr, false); err != nil {
+		return "", err
+	}
+	return t.Get_Str(idx), nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_U8(idx PIdx_U8, val uint8) error {
+	if err := t.setRootErr(uint32(idx), "Uint8", typeU8, false); err != nil {
+		return err
+	}
+	t.SetRoot_U8(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_I8(idx PIdx_I8, val int8) error {
+	if err := t.setRootErr(uint32(idx), "Int8", typeI8, false); err != nil {
+		return err
+	}
+	t.SetRoot_I8(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_Bool(idx PIdx_Bool, val bool) error {
+	if err := t.setRootErr(uint32(idx), "Bool", typeBool, true); err != nil {
+		return err
+	}
+	t.SetRoot_Bool(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_U16(idx PIdx_U16, val uint16) error {
+	if err := t.setRootErr(uint32(idx), "Uint16", typeU16, false); err != nil {
+		return err
+	}
+	t.SetRoot_U16(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_I16(idx PIdx_I16, val int16) error {
+	if err := t.setRootErr(uint32(idx), "Int16", typeI16, false); err != nil {
+		return err
+	}
+	t.SetRoot_I16(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_U32(idx PIdx_U32, val uint32) error {
+	if err := t.setRootErr(uint32(idx), "Uint32", typeU32, false); err != nil {
+		return err
+	}
+	t.SetRoot_U32(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_I32(idx PIdx_I32, val int32) error {
+	if err := t.setRootErr(uint32(idx), "Int32", typeI32, false); err != nil {
+		return err
+	}
+	t.SetRoot_I32(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_F32(idx PIdx_F32, val float32) error {
+	if err := t.setRootErr(uint32(idx), "Float32", typeF32, false); err != nil {
+		return err
+	}
+	t.SetRoot_F32(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_U64(idx PIdx_U64, val uint64) error {
+	if err := t.setRootErr(uint32(idx), "Uint64", typeU64, false); err != nil {
+		return err
+	}
+	t.SetRoot_U64(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_I64(idx PIdx_I64, val int64) error {
+	if err := t.setRootErr(uint32(idx), "Int64", typeI64, false); err != nil {
+		return err
+	}
+	t.SetRoot_I64(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_F64(idx PIdx_F64, val float64) error {
+	if err := t.setRootErr(uint32(idx), "Float64", typeF64, false); err != nil {
+		return err
+	}
+	t.SetRoot_F64(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_Ptr(idx PIdx_Ptr, val unsafe.Pointer) error {
+	if err := t.setRootErr(uint32(idx), "unsafe.Pointer", typePtr, false); err != nil {
+		return err
+	}
+	t.SetRoot_Ptr(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TrySetRoot_Str(idx PIdx_Str, val string) error {
+	if err := t.setRootErr(uint32(idx), "String", typeStr, false); err != nil {
+		return err
+	}
+	t.SetRoot_Str(idx, val)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_U8(idx PIdx_U8, val uint8, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Uint8", typeU8, false); err != nil {
+		return err
+	}
+	t.InitRoot_U8(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_I8(idx PIdx_I8, val int8, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Int8", typeI8, false); err != nil {
+		return err
+	}
+	t.InitRoot_I8(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_Bool(idx PIdx_Bool, val bool, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Bool", typeBool, true); err != nil {
+		return err
+	}
+	t.InitRoot_Bool(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_U16(idx PIdx_U16, val uint16, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Uint16", typeU16, false); err != nil {
+		return err
+	}
+	t.InitRoot_U16(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_I16(idx PIdx_I16, val int16, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Int16", typeI16, false); err != nil {
+		return err
+	}
+	t.InitRoot_I16(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_U32(idx PIdx_U32, val uint32, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Uint32", typeU32, false); err != nil {
+		return err
+	}
+	t.InitRoot_U32(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_I32(idx PIdx_I32, val int32, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Int32", typeI32, false); err != nil {
+		return err
+	}
+	t.InitRoot_I32(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_F32(idx PIdx_F32, val float32, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Float32", typeF32, false); err != nil {
+		return err
+	}
+	t.InitRoot_F32(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_U64(idx PIdx_U64, val uint64, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Uint64", typeU64, false); err != nil {
+		return err
+	}
+	t.InitRoot_U64(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_I64(idx PIdx_I64, val int64, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Int64", typeI64, false); err != nil {
+		return err
+	}
+	t.InitRoot_I64(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_F64(idx PIdx_F64, val float64, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "Float64", typeF64, false); err != nil {
+		return err
+	}
+	t.InitRoot_F64(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_Ptr(idx PIdx_Ptr, val unsafe.Pointer, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "unsafe.Pointer", typePtr, false); err != nil {
+		return err
+	}
+	t.InitRoot_Ptr(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitRoot_Str(idx PIdx_Str, val string, alwaysUpdate bool) error {
+	if err := t.initRootErr(uint32(idx), "String", typeStr, false); err != nil {
+		return err
+	}
+	t.InitRoot_Str(idx, val, alwaysUpdate)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_U8(idx PIdx_U8, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Uint8", typeU8, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_U8(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_I8(idx PIdx_I8, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Int8", typeI8, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_I8(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_Bool(idx PIdx_Bool, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Bool", typeBool, true, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_Bool(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_U16(idx PIdx_U16, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Uint16", typeU16, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_U16(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_I16(idx PIdx_I16, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Int16", typeI16, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_I16(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_U32(idx PIdx_U32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Uint32", typeU32, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_U32(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_I32(idx PIdx_I32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Int32", typeI32, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_I32(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_F32(idx PIdx_F32, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Float32", typeF32, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_F32(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_U64(idx PIdx_U64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Uint64", typeU64, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_U64(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_I64(idx PIdx_I64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Int64", typeI64, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_I64(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_F64(idx PIdx_F64, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "Float64", typeF64, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_F64(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_Ptr(idx PIdx_Ptr, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "unsafe.Pointer", typePtr, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_Ptr(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
+
+func (t *ParamTableOf[E]) TryInitDerived_Str(idx PIdx_Str, alwaysUpdate bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(idx), "String", typeStr, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(idx), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	t.InitDerived_Str(idx, alwaysUpdate, calcIdx, inputs, outputs)
+	return nil
+}
@@ -0,0 +1,191 @@
+package go_param_table
+
+import (
+	"reflect"
+)
+
+// A parameter index of an opaque value: a value of any Go type (a struct, a slice, a time.Time, ...)
+// that is stored as-is where the garbage collector can see it. Opaque values are read and written
+// with the generic *Val functions (GetVal(), SetVal(), InputVal(), OutputVal(), ...), and can be
+// roots, derived values and calculation inputs like any other parameter.
+//
+// Each opaque value is initialized with an equality func that decides whether a write changed it.
+// Use Equal for comparable types, a method expression such as time.Time.Equal, or nil to treat
+// every write as a change. Writing an opaque value stores the value itself, so a slice or map
+// shared with the table must not be modified afterwards, write a new one instead.
+//
+// Opaque values have their own region, after the string region. It is declared with DeclareVal() on
+// a TableBuilder or ParamLayout.ValEnd with NewParamTableFromLayout(); NewParamTable() leaves it empty
+type PIdx_Val[T any] uint32
+
+func (p PIdx_Val[T]) Index() uint32 {
+	return uint32(p)
+}
+
+func (p PIdx_Val[T]) typeIdx() int {
+	return typeVal
+}
+
+// The equality func of comparable types, for InitRootVal() and InitDerivedVal()
+func Equal[T comparable](a, b T) bool {
+	return a == b
+}
+
+// Returns the current value of the opaque parameter
+func GetVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T]) T {
+	idx := uint32(p)
+	t.checkIdxOfType(idx, typeVal, true)
+	t.checkInit(idx)
+	val, ok := t.vals[idx-t.idxOffsets[typeVal]].(T)
+	if !ok && t.debug.enabled {
+		if err := valTypeErr[T](t, idx); err != nil {
+			t.fail(err)
+		}
+	}
+	return val
+}
+
+// Sets the value of an opaque root parameter and updates all values derived from it
+// (or stages it until CommitBatch() if a batch is open)
+func SetVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], val T) {
+	idx := uint32(p)
+	t.checkInit(idx)
+	if t.history.wrapsSet(len(t.batch.starts)) {
+		t.BeginBatch()
+		SetVal(t, p, val)
+		t.CommitBatch()
+		return
+	}
+	t.stageRoot(idx, typeVal)
+	if setVal(t, idx, val, false) {
+		t.rootChanged(E(idx))
+	}
+}
+
+// Initializes an opaque root parameter with its first value and the equality func used to
+// detect changes when it is set. Like InitRoot(), panics if a batch is open, and re-initializing
+// a root while the undo history is enabled is recorded like SetVal()
+func InitRootVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], val T, alwaysUpdate bool, equal func(a, b T) bool) {
+	idx := uint32(p)
+	t.checkIdxOfType(idx, typeVal, false)
+	t.checkNoBatch("InitRootVal")
+	wasInit := getFlag(idx, t.flags).IsInit()
+	f := _PFLAG_INIT
+	if alwaysUpdate {
+		f |= _PFLAG_ALWAYS_UPDATE
+	}
+	setFlag(idx, t.flags, f)
+	t.valEqual[idx-t.idxOffsets[typeVal]] = boxEqual(equal)
+	if wasInit && t.history.wrapsSet(0) {
+		SetVal(t, p, val)
+		return
+	}
+	if setVal(t, idx, val, false) {
+		t.propagateFrom(E(idx))
+	}
+}
+
+// Initializes an opaque derived parameter, calculated by calcIdx from inputs and written to
+// outputs (which normally includes p itself). equal detects whether a calculation changed it
+func InitDerivedVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], alwaysUpdate bool, equal func(a, b T) bool, calcIdx PIdx_Calc, inputs []E, outputs []E) {
+	idx := uint32(p)
+	t.checkIdxOfType(idx, typeVal, true)
+	t.valEqual[idx-t.idxOffsets[typeVal]] = boxEqual(equal)
+	t.initDerivedHookups(E(idx), alwaysUpdate, calcIdx, inputs, outputs)
+}
+
+// Returns the value of an opaque calculation input
+func InputVal[T any, E Index](c *CalcInterfaceOf[E], inputIdx uint16) T {
+	return GetVal(c.table, PIdx_Val[T](c.inputs[inputIdx]))
+}
+
+// Sets the value of an opaque calculation output
+func OutputVal[T any, E Index](c *CalcInterfaceOf[E], outputIdx uint16, val T) {
+	idx := c.outputs[outputIdx]
+	if setVal(c.table, uint32(idx), val, true) {
+		c.markChanged(idx)
+	}
+}
+
+// Same as GetVal(), but returns an error instead of panicking
+func TryGetVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T]) (T, error) {
+	var zero T
+	if err := t.getErr(uint32(p), typeNames[typeVal], typeVal, false); err != nil {
+		return zero, err
+	}
+	if err := valTypeErr[T](t, uint32(p)); err != nil {
+		return zero, err
+	}
+	return GetVal(t, p), nil
+}
+
+// Same as SetVal(), but returns an error instead of panicking
+func TrySetVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], val T) error {
+	if err := t.setRootErr(uint32(p), typeNames[typeVal], typeVal, false); err != nil {
+		return err
+	}
+	if err := valTypeErr[T](t, uint32(p)); err != nil {
+		return err
+	}
+	SetVal(t, p, val)
+	return nil
+}
+
+// Same as InitRootVal(), but returns an error instead of panicking
+func TryInitRootVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], val T, alwaysUpdate bool, equal func(a, b T) bool) error {
+	if err := t.initRootErr(uint32(p), typeNames[typeVal], typeVal, false); err != nil {
+		return err
+	}
+	InitRootVal(t, p, val, alwaysUpdate, equal)
+	return nil
+}
+
+// Same as InitDerivedVal(), but returns an error instead of panicking
+func TryInitDerivedVal[T any, E Index](t *ParamTableOf[E], p PIdx_Val[T], alwaysUpdate bool, equal func(a, b T) bool, calcIdx PIdx_Calc, inputs []E, outputs []E) error {
+	if err := t.idxTypeErr(uint32(p), typeNames[typeVal], typeVal, false, true); err != nil {
+		return err
+	}
+	if err := t.hookupErr(E(p), calcIdx, inputs, outputs, true); err != nil {
+		return err
+	}
+	InitDerivedVal(t, p, alwaysUpdate, equal, calcIdx, inputs, outputs)
+	return nil
+}
+
+func setVal[T any, E Index](t *ParamTableOf[E], idx uint32, val T, canBeDerived bool) (changed bool) {
+	t.checkIdxOfType(idx, typeVal, canBeDerived)
+	if t.debug.enabled {
+		if err := valTypeErr[T](t, idx); err != nil {
+			t.fail(err)
+		}
+	}
+	subIdx := idx - t.idxOffsets[typeVal]
+	oldVal := t.vals[subIdx]
+	t.vals[subIdx] = val
+	return getFlag(idx, t.flags).AlwaysUpdate() || !t.valsEqual(subIdx, oldVal, t.vals[subIdx])
+}
+
+// Whether two values of the opaque parameter at subIdx are equal according to its equality func.
+// Unset values and values without an equality func are never equal
+func (t *ParamTableOf[E]) valsEqual(subIdx uint32, a any, b any) bool {
+	equal := t.valEqual[subIdx]
+	return equal != nil && a != nil && b != nil && equal(a, b)
+}
+
+func boxEqual[T any](equal func(a, b T) bool) func(a, b any) bool {
+	if equal == nil {
+		return nil
+	}
+	return func(a, b any) bool {
+		return equal(a.(T), b.(T))
+	}
+}
+
+// Returns an ErrWrongType error if the opaque parameter at idx holds a value that is not a T
+func valTypeErr[T any, E Index](t *ParamTableOf[E], idx uint32) error {
+	stored := t.vals[idx-t.idxOffsets[typeVal]]
+	if _, ok := stored.(T); ok || stored == nil {
+		return nil
+	}
+	return newParamError(ErrWrongType, idx, "index %s holds a %T value, not a %s", t.paramLabel(idx), stored, reflect.TypeOf((*T)(nil)).Elem())
+}
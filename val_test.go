@@ -0,0 +1,153 @@
+package go_param_table
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRect struct {
+	X, Y, W, H float64
+}
+
+func TestOpaqueValueParams(t *testing.T) {
+	t.Run("narrow", testOpaqueValueParams[uint16])
+	t.Run("wide", testOpaqueValueParams[uint32])
+}
+
+func testOpaqueValueParams[E Index](t *testing.T) {
+	const (
+		Pad PIdx_F64 = iota
+		_F64_PARAMS_END
+	)
+	const (
+		Rects           PIdx_Val[[]testRect] = PIdx_Val[[]testRect](iota + _F64_PARAMS_END)
+		Bounds          PIdx_Val[testRect]   = PIdx_Val[testRect](iota + _F64_PARAMS_END)
+		Created         PIdx_Val[time.Time]  = PIdx_Val[time.Time](iota + _F64_PARAMS_END)
+		Expires         PIdx_Val[time.Time]  = PIdx_Val[time.Time](iota + _F64_PARAMS_END)
+		_VAL_PARAMS_END PIdx_Val[any]        = PIdx_Val[any](iota + _F64_PARAMS_END)
+	)
+	const (
+		calcBounds PIdx_Calc = iota
+		calcExpires
+		_CALC_COUNT
+	)
+	table := newTestTableOf[E](ParamLayout{F64End: _F64_PARAMS_END, ValEnd: _VAL_PARAMS_END}, _CALC_COUNT,
+		WithDebug(true), WithVerbosity(VerbositySilent))
+	expiresEvals := 0
+	table.RegisterCalc(calcBounds, func(c *CalcInterfaceOf[E]) {
+		pad := c.GetInput_F64(0)
+		rects := InputVal[[]testRect](c, 1)
+		minX, minY, maxX, maxY := rects[0].X, rects[0].Y, rects[0].X+rects[0].W, rects[0].Y+rects[0].H
+		for _, r := range rects[1:] {
+			minX, minY = min(minX, r.X), min(minY, r.Y)
+			maxX, maxY = max(maxX, r.X+r.W), max(maxY, r.Y+r.H)
+		}
+		OutputVal(c, 0, testRect{minX - pad, minY - pad, maxX - minX + 2*pad, maxY - minY + 2*pad})
+	})
+	table.RegisterCalc(calcExpires, func(c *CalcInterfaceOf[E]) {
+		expiresEvals += 1
+		OutputVal(c, 0, InputVal[time.Time](c, 0).Add(time.Hour))
+	})
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	table.InitRoot_F64(Pad, 0, false)
+	InitRootVal(&table, Rects, []testRect{{0, 0, 10, 10}, {20, 5, 10, 10}}, false, nil)
+	InitRootVal(&table, Created, created, false, time.Time.Equal)
+	InitDerivedVal(&table, Bounds, false, Equal[testRect], calcBounds, []E{E(Pad), E(Rects)}, []E{E(Bounds)})
+	InitDerivedVal(&table, Expires, false, time.Time.Equal, calcExpires, []E{E(Created)}, []E{E(Expires)})
+
+	var expectBounds = func(name string, exp testRect) {
+		t.Helper()
+		if got := GetVal(&table, Bounds); got != exp {
+			t.Errorf("%s: bounds:\n\tEXP: %v\n\tGOT: %v", name, exp, got)
+		}
+	}
+	expectBounds("initial", testRect{0, 0, 30, 15})
+	if got := GetVal(&table, Expires); !got.Equal(created.Add(time.Hour)) {
+		t.Errorf("initial: expires:\n\tEXP: %v\n\tGOT: %v", created.Add(time.Hour), got)
+	}
+
+	SetVal(&table, Rects, []testRect{{-5, 0, 10, 10}})
+	table.SetRoot_F64(Pad, 1)
+	expectBounds("set roots", testRect{-6, -1, 12, 12})
+
+	// the same instant in another location is equal according to time.Time.Equal
+	expiresEvals = 0
+	SetVal(&table, Created, created.In(time.FixedZone("UTC+2", 2*60*60)))
+	if expiresEvals != 0 {
+		t.Errorf("equal times triggered %d evaluations", expiresEvals)
+	}
+	SetVal(&table, Created, created.Add(time.Minute))
+	if expiresEvals != 1 {
+		t.Errorf("changed time triggered %d evaluations", expiresEvals)
+	}
+
+	table.BeginBatch()
+	SetVal(&table, Rects, []testRect{{0, 0, 1, 1}, {1, 1, 1, 1}})
+	table.SetRoot_F64(Pad, 0)
+	table.CommitBatch()
+	expectBounds("batch", testRect{0, 0, 2, 2})
+	table.BeginBatch()
+	SetVal(&table, Rects, nil)
+	table.Rollback()
+	if got := GetVal(&table, Rects); len(got) != 2 {
+		t.Errorf("rollback:\n\tEXP: 2 rects\n\tGOT: %v", got)
+	}
+
+	if _, err := TryGetVal(&table, PIdx_Val[string](Rects)); !errors.Is(err, ErrWrongType) {
+		t.Errorf("get with the wrong value type:\n\tEXP: %v\n\tGOT: %v", ErrWrongType, err)
+	}
+	if err := TrySetVal(&table, PIdx_Val[testRect](Pad), testRect{}); !errors.Is(err, ErrWrongType) {
+		t.Errorf("set float index:\n\tEXP: %v\n\tGOT: %v", ErrWrongType, err)
+	}
+	if err := TrySetVal(&table, Bounds, testRect{}); !errors.Is(err, ErrDerivedNotSettable) {
+		t.Errorf("set derived value:\n\tEXP: %v\n\tGOT: %v", ErrDerivedNotSettable, err)
+	}
+	if table.TypeOf(E(Bounds)) != TypeVal || TypeVal.String() != "Value" {
+		t.Errorf("type error:\n\tEXP: %v\n\tGOT: %v", TypeVal, table.TypeOf(E(Bounds)))
+	}
+	if !slices.Equal(table.Parents(E(Bounds)), []E{E(Pad), E(Rects)}) {
+		t.Errorf("parents error: %v", table.Parents(E(Bounds)))
+	}
+	var dot bytes.Buffer
+	if err := table.WriteDOT(&dot); err != nil || !strings.Contains(dot.String(), "Value = {0 0 2 2}") {
+		t.Errorf("graph is missing the opaque value (%v):\n%s", err, dot.String())
+	}
+
+	table.RemoveDerived(E(Expires))
+	if sub := uint32(Expires) - uint32(_F64_PARAMS_END); table.vals[sub] != nil || table.valEqual[sub] != nil {
+		t.Errorf("removed opaque value was not cleared")
+	}
+}
+
+func TestOpaqueValueBuilder(t *testing.T) {
+	t.Run("narrow", testOpaqueValueBuilder[uint16])
+	t.Run("wide", testOpaqueValueBuilder[uint32])
+}
+
+func testOpaqueValueBuilder[E Index](t *testing.T) {
+	b := newTestBuilderOf[E](WithDebug(true))
+	count := b.U32("count")
+	names := DeclareVal[[]string](b, "names")
+	joined := b.Str("joined")
+	calcJoin := b.Calc("join", func(c *CalcInterfaceOf[E]) {
+		c.SetOutput_Str(0, strings.Join(InputVal[[]string](c, 0)[:c.GetInput_U32(1)], ","))
+	})
+	table := b.Build()
+	table.InitRoot_U32(*count, 2, false)
+	InitRootVal(&table, *names, []string{"a", "b", "c"}, false, slices.Equal[[]string])
+	table.InitDerived_Str(*joined, false, *calcJoin, []E{E(*names), E(*count)}, []E{E(*joined)})
+	if got := table.Get_Str(*joined); got != "a,b" {
+		t.Errorf("joined:\n\tEXP: %q\n\tGOT: %q", "a,b", got)
+	}
+	if idx, ok := table.LookupByName("names"); !ok || table.TypeOf(idx) != TypeVal {
+		t.Errorf("declared opaque value has type %v", table.TypeOf(idx))
+	}
+	SetVal(&table, *names, []string{"x", "y"})
+	if got := table.Get_Str(*joined); got != "x,y" {
+		t.Errorf("joined after set:\n\tEXP: %q\n\tGOT: %q", "x,y", got)
+	}
+}
@@ -0,0 +1,289 @@
+package go_param_table
+
+import (
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// How a Watcher handles a change when its buffer is full
+type OverflowPolicy uint8
+
+const (
+	// Drops the oldest buffered event to make room for the new one
+	OverflowDropOldest OverflowPolicy = iota
+	// Merges the new event into the buffered event of the same index, which keeps its place and
+	// old value but takes the new value and batch id. Drops the oldest buffered event if none
+	// has the same index
+	OverflowCoalesce
+	// Blocks the update that made the change until the consumer receives an event or the
+	// Watcher is closed. The consumer must then never wait on the goroutine updating the table
+	OverflowBlock
+)
+
+// The number of events a Watcher buffers by default
+const DefaultWatchBuffer = 64
+
+// An option passed to Watch()
+type WatchOption func(w *watchConfig)
+
+type watchConfig struct {
+	buffer   int
+	overflow OverflowPolicy
+}
+
+// Sets the number of events the channel buffers before the overflow policy applies. Values
+// below 1 are treated as 1
+func WithWatchBuffer(size int) WatchOption {
+	return func(w *watchConfig) {
+		w.buffer = max(size, 1)
+	}
+}
+
+// Sets what happens when the buffer is full, OverflowDropOldest by default
+func WithOverflow(policy OverflowPolicy) WatchOption {
+	return func(w *watchConfig) {
+		w.overflow = policy
+	}
+}
+
+// Selects the parameters a Watcher reports. A parameter is watched if it is listed in Idxs, is of
+// one of the Types, or has one of the Tags in its ParamMeta. A filter whose lists are all nil or
+// empty, like the zero WatchFilter, watches every parameter. Types and tags are matched when
+// Watch() is called, tags set later have no effect
+type WatchFilter struct {
+	Idxs  []uint32
+	Types []ParamType
+	Tags  []string
+}
+
+// A change of one parameter, see Watch()
+type ChangeEvent struct {
+	Idx  uint32
+	Type ParamType
+	// The values before and after the change, of the Go type of the parameter (float64 for a
+	// Float64 parameter, unsafe.Pointer for a pointer parameter, ...)
+	Old any
+	New any
+	// Identifies the update that made the change: all changes of one SetRoot_*() call or one
+	// outermost batch (and the changes made by listeners of them) share an id. Ids start at 1
+	// and increase with every update that changes a watched or subscribed parameter
+	Batch uint64
+}
+
+// Receives the changes selected by a WatchFilter, see Watch()
+type Watcher struct {
+	// Receives one event per changed parameter, in the order Subscribe() listeners are called.
+	// Closed by Close()
+	C <-chan ChangeEvent
+	w *watcher
+}
+
+type watcher struct {
+	// held while sending, so that Close() never closes out during a send
+	mu        sync.Mutex
+	out       chan ChangeEvent
+	done      chan struct{}
+	closed    bool
+	closeOnce sync.Once
+	config    watchConfig
+	// events taken out of out to coalesce them, reused
+	scratch []ChangeEvent
+	// set by Watch() before the Watcher is returned, not modified afterwards
+	subs []Subscription
+}
+
+// Stops the Watcher: its listeners are removed from the table before Close() returns, and C is
+// closed once the events still buffered in it are received. Unlike every other method of a
+// table, it may be called from any goroutine, including while an update waits on a full
+// OverflowBlock buffer. Calling it more than once has no effect
+func (w *Watcher) Close() {
+	w.w.closeOnce.Do(func() {
+		// unblocks a send of OverflowBlock, which holds mu
+		close(w.w.done)
+		w.w.mu.Lock()
+		w.w.closed = true
+		close(w.w.out)
+		w.w.mu.Unlock()
+		for _, sub := range w.w.subs {
+			sub.Unsubscribe()
+		}
+	})
+}
+
+// Returns a channel that receives every change of the parameters selected by filter, for
+// consumers in other goroutines. Events are sent when Subscribe() listeners would be called,
+// once the update that made the change has fully propagated. A filter without any Idxs, Types
+// or Tags (nil or empty) watches every parameter of the table. Panics if filter lists an index
+// outside the parameter list (see TryWatch())
+func (t *ParamTableOf[E]) Watch(filter WatchFilter, opts ...WatchOption) *Watcher {
+	config := watchConfig{buffer: DefaultWatchBuffer}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	w := &watcher{
+		out:    make(chan ChangeEvent, config.buffer),
+		done:   make(chan struct{}),
+		config: config,
+	}
+	for _, idx := range t.watchedIdxs(&filter) {
+		idx, typeIdx := idx, t.typeOfIdx(idx)
+		w.subs = append(w.subs, t.subscribe(idx, typeIdx, func(old, new *savedValue) {
+			event := ChangeEvent{
+				Idx:   idx,
+				Type:  ParamType(typeIdx),
+				Old:   savedAny(old, typeIdx),
+				New:   savedAny(new, typeIdx),
+				Batch: t.subs.batch,
+			}
+			w.send(event)
+		}))
+	}
+	return &Watcher{C: w.out, w: w}
+}
+
+// Same as Watch(), but returns an ErrIndexOutOfRange error instead of panicking
+func (t *ParamTableOf[E]) TryWatch(filter WatchFilter, opts ...WatchOption) (*Watcher, error) {
+	for _, idx := range filter.Idxs {
+		if idx >= uint32(len(t.hookups)) {
+			return nil, newParamError(ErrIndexOutOfRange, idx, "Watch(): index %d is outside bounds of parameter list (len %d)", idx, len(t.hookups))
+		}
+	}
+	return t.Watch(filter, opts...), nil
+}
+
+// Returns the indexes selected by filter, in order
+func (t *ParamTableOf[E]) watchedIdxs(filter *WatchFilter) []uint32 {
+	var idxs []uint32
+	if len(filter.Idxs) == 0 && len(filter.Types) == 0 && len(filter.Tags) == 0 {
+		idxs = make([]uint32, len(t.hookups))
+		for i := range idxs {
+			idxs[i] = uint32(i)
+		}
+		return idxs
+	}
+	for _, idx := range filter.Idxs {
+		if idx >= uint32(len(t.hookups)) {
+			t.fail(newParamError(ErrIndexOutOfRange, idx, "Watch(): index %d is outside bounds of parameter list (len %d)", idx, len(t.hookups)))
+		}
+		idxs = append(idxs, idx)
+	}
+	for _, typ := range filter.Types {
+		if int(typ) >= typeCount {
+			continue
+		}
+		for idx := t.idxOffsets[typ]; idx < t.regionEnd(int(typ)); idx += 1 {
+			idxs = append(idxs, idx)
+		}
+	}
+	if len(filter.Tags) > 0 {
+		for idx, meta := range t.meta.params {
+			if slices.ContainsFunc(meta.Tags, func(tag string) bool { return slices.Contains(filter.Tags, tag) }) {
+				idxs = append(idxs, uint32(idx))
+			}
+		}
+	}
+	slices.Sort(idxs)
+	return slices.Compact(idxs)
+}
+
+// The index after the last parameter of the type region
+func (t *ParamTableOf[E]) regionEnd(typeIdx int) uint32 {
+	if typeIdx == typeCount-1 {
+		return uint32(len(t.hookups))
+	}
+	return t.idxOffsets[typeIdx+1]
+}
+
+// Sends the event according to the overflow policy. Does nothing once the Watcher is closed,
+// for a change notified while Close() runs
+func (w *watcher) send(event ChangeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.out <- event:
+		return
+	default:
+	}
+	switch w.config.overflow {
+	case OverflowBlock:
+		select {
+		case w.out <- event:
+		case <-w.done:
+		}
+	case OverflowCoalesce:
+		w.scratch = w.scratch[:0]
+		for drained := false; !drained; {
+			select {
+			case buffered := <-w.out:
+				w.scratch = append(w.scratch, buffered)
+			default:
+				drained = true
+			}
+		}
+		if i := slices.IndexFunc(w.scratch, func(e ChangeEvent) bool { return e.Idx == event.Idx }); i >= 0 {
+			w.scratch[i].New = event.New
+			w.scratch[i].Batch = event.Batch
+		} else {
+			w.scratch = append(w.scratch, event)
+		}
+		if len(w.scratch) > w.config.buffer {
+			w.scratch = slices.Delete(w.scratch, 0, 1)
+		}
+		for _, e := range w.scratch {
+			w.out <- e
+		}
+		clear(w.scratch)
+	default:
+		// the consumer may have made room in the meantime, so the oldest event is only dropped
+		// if the buffer is still full
+		for {
+			select {
+			case w.out <- event:
+				return
+			default:
+			}
+			select {
+			case <-w.out:
+			default:
+			}
+		}
+	}
+}
+
+// Returns a value copied by saveValue() as its Go type
+func savedAny(v *savedValue, typeIdx int) any {
+	ptr := unsafe.Pointer(&v.raw)
+	switch typeIdx {
+	case typeU64:
+		return *(*uint64)(ptr)
+	case typeI64:
+		return *(*int64)(ptr)
+	case typeF64:
+		return *(*float64)(ptr)
+	case typePtr:
+		return v.ptr
+	case typeStr:
+		return v.str
+	case typeVal:
+		return v.val
+	case typeU32:
+		return *(*uint32)(ptr)
+	case typeI32:
+		return *(*int32)(ptr)
+	case typeF32:
+		return *(*float32)(ptr)
+	case typeU16:
+		return *(*uint16)(ptr)
+	case typeI16:
+		return *(*int16)(ptr)
+	case typeU8:
+		return *(*uint8)(ptr)
+	case typeI8:
+		return *(*int8)(ptr)
+	}
+	return v.raw[0] != 0
+}
@@ -0,0 +1,191 @@
+package go_param_table
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Receives every event buffered in the channel without waiting for more
+func drainEvents(c <-chan ChangeEvent) (events []ChangeEvent) {
+	for {
+		select {
+		case e, ok := <-c:
+			if !ok {
+				return
+			}
+			events = append(events, e)
+		default:
+			return
+		}
+	}
+}
+
+func expectEvents(t *testing.T, name string, got []ChangeEvent, exp ...ChangeEvent) {
+	t.Helper()
+	if !slices.Equal(got, exp) {
+		t.Errorf("%s:\n\tEXP: %v\n\tGOT: %v", name, exp, got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("narrow", testWatch[uint16])
+	t.Run("wide", testWatch[uint32])
+}
+
+func testWatch[E Index](t *testing.T) {
+	// 0 -> 1, 2 -> 3 = 1 + 2
+	table, _ := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.InitRoot_F64(0, 1, false)
+	derive_F64(table, 1, _TEST_CALC_ADD_ONE, 0)
+	table.InitRoot_F64(2, 10, false)
+	derive_F64(table, 3, _TEST_CALC_SUM, 1, 2)
+	w := table.Watch(WatchFilter{})
+	table.SetRoot_F64(0, 5)
+	expectEvents(t, "set", drainEvents(w.C),
+		ChangeEvent{0, TypeF64, 1.0, 5.0, 1},
+		ChangeEvent{1, TypeF64, 2.0, 6.0, 1},
+		ChangeEvent{3, TypeF64, 12.0, 16.0, 1},
+	)
+	table.BeginBatch()
+	table.SetRoot_F64(2, 20)
+	table.SetRoot_F64(0, 7)
+	table.CommitBatch()
+	expectEvents(t, "batch", drainEvents(w.C),
+		ChangeEvent{2, TypeF64, 10.0, 20.0, 2},
+		ChangeEvent{0, TypeF64, 5.0, 7.0, 2},
+		ChangeEvent{1, TypeF64, 6.0, 8.0, 2},
+		ChangeEvent{3, TypeF64, 16.0, 28.0, 2},
+	)
+
+	// the listeners are removed right away, the channel is closed once buffered events are received
+	table.SetRoot_F64(2, 21)
+	w.Close()
+	if len(table.subs.params) != 0 {
+		t.Errorf("closed watcher still has %d listeners", len(table.subs.params))
+	}
+	w.Close()
+	var received []ChangeEvent
+	for e := range w.C {
+		received = append(received, e)
+	}
+	expectEvents(t, "closed", received, ChangeEvent{2, TypeF64, 20.0, 21.0, 3}, ChangeEvent{3, TypeF64, 28.0, 29.0, 3})
+
+	// empty lists select every parameter, like the zero filter
+	all := table.Watch(WatchFilter{Idxs: []uint32{}, Tags: []string{}})
+	if len(table.subs.params) != 4 {
+		t.Errorf("filter with empty lists:\n\tEXP: 4 watched parameters\n\tGOT: %d", len(table.subs.params))
+	}
+	all.Close()
+
+	// closed by the consumer while the table keeps updating
+	concurrent := table.Watch(WatchFilter{Idxs: []uint32{0}}, WithWatchBuffer(1))
+	consumed := make(chan struct{})
+	go func() {
+		<-concurrent.C
+		concurrent.Close()
+		for range concurrent.C {
+		}
+		close(consumed)
+	}()
+	for i := 0; i < 1000; i += 1 {
+		table.SetRoot_F64(0, float64(i))
+	}
+	<-consumed
+	if len(table.subs.params) != 0 {
+		t.Errorf("watcher closed by the consumer still has %d listeners", len(table.subs.params))
+	}
+
+	if _, err := table.TryWatch(WatchFilter{Idxs: []uint32{4}}); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("watch out of range:\n\tEXP: %v\n\tGOT: %v", ErrIndexOutOfRange, err)
+	}
+}
+
+func TestWatchFilter(t *testing.T) {
+	t.Run("narrow", testWatchFilter[uint16])
+	t.Run("wide", testWatchFilter[uint32])
+}
+
+func testWatchFilter[E Index](t *testing.T) {
+	g := newBinaryTestTable[E]()
+	g.table.SetMeta(E(g.count), ParamMeta{Name: "count", Tags: []string{"ui", "stats"}})
+	w := g.table.Watch(WatchFilter{
+		Idxs:  []uint32{uint32(g.area)},
+		Types: []ParamType{TypeBool},
+		Tags:  []string{"ui"},
+	})
+	defer w.Close()
+	g.table.SetRoot_F64(g.width, 4)
+	g.table.SetRoot_U8(g.count, 5)
+	g.table.SetRoot_I16(g.offset, 5)
+	g.table.InitRoot_Bool(g.enabled, true, false)
+	expectEvents(t, "filter", drainEvents(w.C),
+		ChangeEvent{uint32(g.area), TypeF64, 6.0, 12.0, 1},
+		ChangeEvent{uint32(g.count), TypeU8, uint8(1), uint8(5), 2},
+		ChangeEvent{uint32(g.enabled), TypeBool, false, true, 3},
+	)
+}
+
+func TestWatchOverflow(t *testing.T) {
+	t.Run("narrow", testWatchOverflow[uint16])
+	t.Run("wide", testWatchOverflow[uint32])
+}
+
+func testWatchOverflow[E Index](t *testing.T) {
+	table, _ := newF64TestTableOf[E](4, WithDebug(true), WithVerbosity(VerbositySilent))
+	for idx := PIdx_F64(0); idx < 4; idx += 1 {
+		table.InitRoot_F64(idx, 0, false)
+	}
+	dropping := table.Watch(WatchFilter{Idxs: []uint32{0}}, WithWatchBuffer(2))
+	for i := 1; i <= 4; i += 1 {
+		table.SetRoot_F64(0, float64(i))
+	}
+	expectEvents(t, "drop oldest", drainEvents(dropping.C), ChangeEvent{0, TypeF64, 2.0, 3.0, 3}, ChangeEvent{0, TypeF64, 3.0, 4.0, 4})
+	dropping.Close()
+
+	coalescing := table.Watch(WatchFilter{Idxs: []uint32{0, 2, 3}}, WithWatchBuffer(2), WithOverflow(OverflowCoalesce))
+	table.SetRoot_F64(0, 5)
+	table.SetRoot_F64(2, 1)
+	table.SetRoot_F64(0, 6)
+	table.SetRoot_F64(2, 2)
+	expectEvents(t, "coalesce", drainEvents(coalescing.C), ChangeEvent{0, TypeF64, 4.0, 6.0, 7}, ChangeEvent{2, TypeF64, 0.0, 2.0, 8})
+	table.SetRoot_F64(0, 7)
+	table.SetRoot_F64(2, 3)
+	table.SetRoot_F64(3, 1)
+	expectEvents(t, "coalesce without a match", drainEvents(coalescing.C), ChangeEvent{2, TypeF64, 2.0, 3.0, 10}, ChangeEvent{3, TypeF64, 0.0, 1.0, 11})
+	coalescing.Close()
+
+	// no event is lost, the update waits for the consumer instead
+	blocking := table.Watch(WatchFilter{Idxs: []uint32{1}}, WithWatchBuffer(1), WithOverflow(OverflowBlock))
+	done := make(chan []ChangeEvent)
+	go func() {
+		var received []ChangeEvent
+		for e := range blocking.C {
+			time.Sleep(time.Millisecond)
+			received = append(received, e)
+			if len(received) == 5 {
+				blocking.Close()
+			}
+		}
+		done <- received
+	}()
+	for i := 1; i <= 5; i += 1 {
+		table.SetRoot_F64(1, float64(i))
+	}
+	if received := <-done; len(received) != 5 || received[4].New != 5.0 {
+		t.Errorf("block:\n\tEXP: 5 events\n\tGOT: %v", received)
+	}
+
+	// closing unblocks an update waiting for the consumer
+	blocking = table.Watch(WatchFilter{Idxs: []uint32{1}}, WithWatchBuffer(1), WithOverflow(OverflowBlock))
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		blocking.Close()
+	}()
+	table.SetRoot_F64(1, 10)
+	table.SetRoot_F64(1, 11)
+	if got := table.Get_F64(1); got != 11 {
+		t.Errorf("closed while blocked:\n\tEXP: 11\n\tGOT: %f", got)
+	}
+}
@@ -0,0 +1,54 @@
+package go_param_table
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWideTableLimits(t *testing.T) {
+	const count = 70000
+	const inputs = 300
+	_, err := TryNewParamTable(0, 0, count, count, count, count, count, count, count, count, count, count, _TEST_CALC_COUNT)
+	if !errors.Is(err, ErrLayout) {
+		t.Errorf("narrow table with %d parameters:\n\tEXP: %v\n\tGOT: %v", count, ErrLayout, err)
+	}
+
+	table := NewWideParamTable(0, 0, count, count, count, count, count, count, count, count, count, count, _TEST_CALC_COUNT, WithDebug(true), WithVerbosity(VerbositySilent))
+	table.RegisterCalc(_TEST_CALC_SUM, func(c *WideCalcInterface) {
+		sum := 0.0
+		for i := range c.GetAllInputs() {
+			sum += c.GetInput_F64(uint16(i))
+		}
+		c.SetOutput_F64(0, sum)
+	})
+	parents := make([]uint32, 0, inputs)
+	for i := uint32(0); i < inputs; i += 1 {
+		idx := count - inputs - 1 + i
+		table.InitRoot_F64(PIdx_F64(idx), float64(i), false)
+		parents = append(parents, idx)
+	}
+	const sink = count - 1
+	table.InitDerived_F64(sink, false, _TEST_CALC_SUM, parents, []uint32{sink})
+	exp := float64(inputs * (inputs - 1) / 2)
+	if got := table.Get_F64(sink); got != exp {
+		t.Errorf("sum of %d inputs:\n\tEXP: %f\n\tGOT: %f", inputs, exp, got)
+	}
+	table.SetRoot_F64(PIdx_F64(parents[0]), 1000)
+	if got := table.Get_F64(sink); got != exp+1000 {
+		t.Errorf("sum after update:\n\tEXP: %f\n\tGOT: %f", exp+1000, got)
+	}
+	if len(table.Parents(sink)) != inputs || table.Children(parents[inputs-1])[0] != sink {
+		t.Errorf("hookup error: %d parents", len(table.Parents(sink)))
+	}
+
+	narrow, _ := newF64TestTable(inputs+1, WithDebug(true), WithVerbosity(VerbositySilent))
+	narrowParents := make([]uint16, inputs)
+	for i := range narrowParents {
+		narrowParents[i] = uint16(i)
+		narrow.InitRoot_F64(PIdx_F64(i), 1, false)
+	}
+	err = narrow.TryInitDerived_F64(inputs, false, _TEST_CALC_SUM, narrowParents, []uint16{inputs})
+	if !errors.Is(err, ErrTooManyHookups) {
+		t.Errorf("narrow table with %d inputs:\n\tEXP: %v\n\tGOT: %v", inputs, ErrTooManyHookups, err)
+	}
+}